@@ -0,0 +1,87 @@
+// signer is a small signing service for zoom.HTTPSigner.  It holds the Meeting SDK secret so the machines running bots don't have to.
+//
+//	ZOOM_API_KEY="xxx" ZOOM_API_SECRET="xxx" SIGNER_TOKEN="xxx" ./signer -listen 127.0.0.1:8787
+//
+// Bots then use zoom.WithSDKKey(key) and zoom.WithSigner(&zoom.HTTPSigner{URL: "http://127.0.0.1:8787/sign", Token: "xxx"})
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/chris124567/zoomer/zoom"
+)
+
+func main() {
+	listen := flag.String("listen", "127.0.0.1:8787", "Address to listen on")
+	meetings := flag.String("meetings", "", "Comma separated meeting numbers to sign for (default any)")
+	allowHost := flag.Bool("allowHost", false, "Sign for role 1 (host) as well as role 0 (attendee)")
+	lifetime := flag.Duration("lifetime", zoom.DefaultSignatureLifetime, "How long signatures are valid for")
+	flag.Parse()
+
+	// get keys from environment
+	apiKey := os.Getenv("ZOOM_API_KEY")
+	apiSecret := os.Getenv("ZOOM_API_SECRET")
+	token := os.Getenv("SIGNER_TOKEN")
+	if apiKey == "" || apiSecret == "" {
+		log.Fatal("ZOOM_API_KEY and ZOOM_API_SECRET must be set")
+	}
+	if token == "" {
+		log.Print("SIGNER_TOKEN is not set, anyone who can reach this server can get signatures")
+	}
+
+	allowedMeetings := make(map[string]bool)
+	for _, meetingNumber := range strings.Split(*meetings, ",") {
+		if meetingNumber = strings.TrimSpace(meetingNumber); meetingNumber != "" {
+			allowedMeetings[meetingNumber] = true
+		}
+	}
+
+	signer := &zoom.HMACSigner{Key: apiKey, Secret: apiSecret, Lifetime: *lifetime}
+	http.Handle("/sign", signHandler(signer, token, allowedMeetings, *allowHost))
+
+	log.Printf("Listening on %s", *listen)
+	log.Fatal(http.ListenAndServe(*listen, nil))
+}
+
+// signHandler answers zoom.HTTPSigner's requests with signatures from signer.  token (if not empty) has to be given as a bearer token, and only meetings in allowedMeetings (if not empty) are signed for
+func signHandler(signer zoom.Signer, token string, allowedMeetings map[string]bool, allowHost bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		var request zoom.SignRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&request); err != nil {
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+		if len(allowedMeetings) > 0 && !allowedMeetings[request.MeetingNumber] {
+			http.Error(w, "Meeting not allowed", http.StatusForbidden)
+			return
+		}
+		if request.Role != zoom.RoleAttendee && !(request.Role == zoom.RoleHost && allowHost) {
+			http.Error(w, "Role not allowed", http.StatusForbidden)
+			return
+		}
+
+		signature, err := signer.Sign(r.Context(), request.MeetingNumber, request.Role)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Printf("Signed for meeting %s with role %d (%s)", request.MeetingNumber, request.Role, r.RemoteAddr)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(zoom.SignResponse{Signature: signature})
+	})
+}
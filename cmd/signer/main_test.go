@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chris124567/zoomer/zoom"
+)
+
+func TestSignHandler(t *testing.T) {
+	server := httptest.NewServer(signHandler(&zoom.HMACSigner{Key: "testKey", Secret: "testSecret"}, "testToken", map[string]bool{"1234567890": true}, false))
+	defer server.Close()
+
+	tests := []struct {
+		name          string
+		token         string
+		meetingNumber string
+		role          int
+		wantErr       string // part of the error, empty for success
+	}{
+		{"attendee", "testToken", "1234567890", zoom.RoleAttendee, ""},
+		{"wrong token", "wrongToken", "1234567890", zoom.RoleAttendee, "401"},
+		{"no token", "", "1234567890", zoom.RoleAttendee, "401"},
+		{"other meeting", "testToken", "1234567891", zoom.RoleAttendee, "Meeting not allowed"},
+		{"host", "testToken", "1234567890", zoom.RoleHost, "Role not allowed"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signer := &zoom.HTTPSigner{URL: server.URL, Token: test.token}
+			signature, err := signer.Sign(context.Background(), test.meetingNumber, test.role)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(strings.Split(signature, ".")) != 3 {
+					t.Errorf("Got signature %q", signature)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Got %v, wanted an error with %q", err, test.wantErr)
+			}
+		})
+	}
+
+	response, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET returned %s", response.Status)
+	}
+}
+
+func TestSignHandlerInvalidMeetingNumber(t *testing.T) {
+	// no allow list, so the signer's own check is what stops this
+	server := httptest.NewServer(signHandler(&zoom.HMACSigner{Key: "testKey", Secret: "testSecret"}, "", nil, true))
+	defer server.Close()
+	signer := &zoom.HTTPSigner{URL: server.URL}
+	if _, err := signer.Sign(context.Background(), `1,"role":1`, zoom.RoleAttendee); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Errorf("Got %v", err)
+	}
+	if _, err := signer.Sign(context.Background(), "1234567890", zoom.RoleHost); err != nil {
+		t.Errorf("Signing as host with allowHost returned %v", err)
+	}
+}
@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/chris124567/zoomer/zoom"
 )
@@ -13,50 +16,93 @@ import (
 func main() {
 	meetingNumber := flag.String("meetingNumber", "", "Meeting number")
 	meetingPassword := flag.String("password", "", "Meeting password")
+	debugProxy := flag.String("debugProxy", "", "Proxy to send all traffic through for debugging (for example Charles or mitmproxy)")
+	debugProxyCA := flag.String("debugProxyCA", "", "PEM file with the debug proxy's CA certificate")
+	waitForHost := flag.Bool("waitForHost", false, "Keep retrying until the host starts the meeting instead of exiting")
+	zak := flag.String("zak", "", "ZAK token of the meeting owner, to join as host")
+	snapshotPath := flag.String("snapshot", "", "File to keep join state in so that a restarted bot rejoins as the same participant")
 	flag.Parse()
 
 	// get keys from environment
 	apiKey := os.Getenv("ZOOM_API_KEY")
 	apiSecret := os.Getenv("ZOOM_API_SECRET")
 
+	options := []zoom.Option{
+		// leave out for meetings without a password
+		zoom.WithPassword(*meetingPassword),
+		zoom.WithDisplayName("Bot"),
+		// hardware uuid (can be random but should be relatively constant or it will appear to zoom that you have many many many devices)
+		zoom.WithHardwareID("ad8ffee7-d47c-4357-9ac8-965ed64e96fc"),
+		// meeting sdk key and secret
+		zoom.WithCredentials(apiKey, apiSecret),
+	}
+	if *zak != "" {
+		options = append(options, zoom.WithZAK(*zak))
+	}
+	if *debugProxy != "" {
+		options = append(options, zoom.WithDebugProxy(*debugProxy, *debugProxyCA))
+	}
+
 	// create new session
-	// meetingNumber, meetingPassword, username, hardware uuid (can be random but should be relatively constant or it will appear to zoom that you have many many many devices), proxy url, jwt api key, jwt api secret)
-	session, err := zoom.NewZoomSession(*meetingNumber, *meetingPassword, "Bot", "ad8ffee7-d47c-4357-9ac8-965ed64e96fc", "", apiKey, apiSecret)
+	session, err := zoom.New(*meetingNumber, options...)
 	if err != nil {
 		panic(err)
 	}
-	// get the rwc token and other info needed to construct the websocket url for the meeting
-	meetingInfo, cookieString, err := session.GetMeetingInfoData()
-	if err != nil {
-		panic(err)
+	ctx := context.Background()
+
+	// pick up where a previous run left off
+	if *snapshotPath != "" {
+		if err := session.RestoreFile(*snapshotPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			panic(err)
+		}
+		// save whenever we (re)join somewhere
+		session.OnStateChange(func(session *zoom.ZoomSession, change *zoom.StateChange) error {
+			if change.To == zoom.StateInMeeting || change.To == zoom.StateInBreakout {
+				return session.SnapshotFile(*snapshotPath)
+			}
+			return nil
+		})
 	}
 
-	// get the url for the websocket connection.  always pass false for the second parameter (its used internally to keep track of some parameters used for getting out of waiting rooms)
-	websocketUrl, err := session.GetWebsocketUrl(meetingInfo, false)
+	// get the rwc token and other info needed to construct the websocket url for the meeting and connect to it
+	if *waitForHost {
+		err = session.WaitForMeetingStart(ctx, 15*time.Second)
+	} else {
+		err = session.Connect(ctx)
+	}
 	if err != nil {
 		panic(err)
 	}
 
-	// the third argument is the "onmessage" function.  it will be triggered everytime the websocket client receives a message
-	panic(session.MakeWebsocketConnection(websocketUrl, cookieString, func(session *zoom.ZoomSession, message zoom.Message) error {
-		switch m := message.(type) {
-		case *zoom.ConferenceRosterIndication:
-			// if we get an indication that someone joined the meeting, welcome them
-			for _, person := range m.Add {
-				// don't welcome ourselves
-				if person.ID != session.JoinInfo.UserID {
-					// you could switch out EVERYONE_CHAT_ID with person.ID to private message them instead of sending the welcome to everyone
-					session.SendChatMessage(zoom.EVERYONE_CHAT_ID, "Welcome to the meeting, "+string(person.Dn2)+"!")
-				}
-			}
-			return nil
-		case *zoom.ConferenceChatIndication:
-			// respond to chats
-			return handleChatMessage(session, m, string(m.Text))
-		default:
-			return nil
+	// leave the meeting cleanly on ctrl+c, this makes Run return
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		leaveCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		session.Leave(leaveCtx)
+	}()
+
+	// if we get an indication that someone joined the meeting, welcome them
+	session.OnRosterAdd(func(session *zoom.ZoomSession, person *zoom.RosterAddItem) error {
+		// don't welcome ourselves
+		if person.ID != session.CurrentJoinInfo().UserID {
+			// you could switch out EVERYONE_CHAT_ID with person.ID to private message them instead of sending the welcome to everyone
+			return session.SendChatMessage(zoom.EVERYONE_CHAT_ID, "Welcome to the meeting, "+string(person.Dn2)+"!")
 		}
-	}))
+		return nil
+	})
+	// respond to chats
+	session.OnChat(func(session *zoom.ZoomSession, message *zoom.ConferenceChatIndication) error {
+		return handleChatMessage(session, message, string(message.Text))
+	})
+
+	// handlers can also be passed as the second argument to Run, which will be called for every message the websocket client receives
+	err = session.Run(ctx, nil)
+	if err != nil {
+		panic(err)
+	}
 }
 
 func handleChatMessage(session *zoom.ZoomSession, body *zoom.ConferenceChatIndication, messageText string) error {
@@ -0,0 +1,246 @@
+package zoom
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// returned by ReturnToMainSession when we are already in the main meeting
+var ErrNotInBreakoutRoom = errors.New("Not in a breakout room")
+
+// returned by runConnection to Run when the connection was closed to move to another room.  result gets the outcome of connecting to the new room
+type switchingRoom struct {
+	result chan<- error
+}
+
+func (err *switchingRoom) Error() string {
+	return "switching room"
+}
+
+// BreakoutRoom returns the BID of the breakout room we are in, or "" if we are in the main meeting
+func (session *ZoomSession) BreakoutRoom() string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.breakoutBID
+}
+
+// JoinBreakoutRoom gets a token for the breakout room with the given BID and moves our connection into it.  Handlers stay registered and Run keeps running on the new connection.
+// Like the web client we can only be in one room at a time, so we disappear from the main meeting until ReturnToMainSession is called.  Both can be called from handlers, for chat commands and the like.
+func (session *ZoomSession) JoinBreakoutRoom(ctx context.Context, bid string) error {
+	response, err := session.JoinBreakoutRoomToken(ctx, bid)
+	if err != nil {
+		return err
+	}
+	if response.Botoken == "" {
+		return errors.New("No breakout room token in join response")
+	}
+	if response.Bid != "" {
+		bid = response.Bid
+	}
+	return session.moveToBreakoutRoom(ctx, bid, response.Botoken)
+}
+
+// moveToBreakoutRoom moves our connection into the breakout room using a token from WS_CONF_BO_JOIN_RES or WS_CONF_BO_COMMAND_INDICATION
+func (session *ZoomSession) moveToBreakoutRoom(ctx context.Context, bid string, botoken string) error {
+	session.stopBreakoutCloseTimer()
+	session.mu.Lock()
+	// going from one breakout room to another should still take us back to the main meeting
+	if session.breakoutBID == "" {
+		session.mainMeetingOpt = session.meetingOpt
+	}
+	previousOpt, previousBID := session.meetingOpt, session.breakoutBID
+	// breakout rooms are joined like the main meeting except with the token as the opt
+	session.meetingOpt = botoken
+	session.breakoutBID = bid
+	session.mu.Unlock()
+
+	session.logger.Info("Joining breakout room", "bid", bid)
+	started, err := session.switchRoom(ctx)
+	if !started {
+		session.mu.Lock()
+		session.meetingOpt, session.breakoutBID = previousOpt, previousBID
+		session.mu.Unlock()
+	}
+	return err
+}
+
+// ReturnToMainSession leaves the breakout room we are in (with WS_CONF_BO_LEAVE_REQ) and moves our connection back to the main meeting
+func (session *ZoomSession) ReturnToMainSession(ctx context.Context) error {
+	session.mu.Lock()
+	bid, previousOpt := session.breakoutBID, session.meetingOpt
+	session.mu.Unlock()
+	if bid == "" {
+		return ErrNotInBreakoutRoom
+	}
+	session.stopBreakoutCloseTimer()
+
+	if err := session.send(WS_CONF_BO_LEAVE_REQ, ConferenceBreakoutRoomLeaveRequest{}); err != nil {
+		return err
+	}
+
+	session.mu.Lock()
+	session.meetingOpt = session.mainMeetingOpt
+	session.breakoutBID = ""
+	session.mu.Unlock()
+
+	session.logger.Info("Returning to main session", "bid", bid)
+	started, err := session.switchRoom(ctx)
+	if !started {
+		session.mu.Lock()
+		session.meetingOpt, session.breakoutBID = previousOpt, bid
+		session.mu.Unlock()
+	}
+	return err
+}
+
+// switchRoom asks Run to drop the current connection and connect again using the current meetingOpt, and waits for the new connection to be made.
+// started is false if Run never got the request, in which case we are still in the old room.  once it has started Run keeps trying (see ReconnectPolicy) even if we give up waiting
+func (session *ZoomSession) switchRoom(ctx context.Context) (started bool, err error) {
+	session.mu.Lock()
+	runDone := session.runDone
+	session.mu.Unlock()
+	if runDone == nil {
+		return false, errors.New("Switching rooms requires Run to be running")
+	}
+
+	result := make(chan error, 1)
+	select {
+	case session.roomSwitches <- result:
+	case <-runDone:
+		return false, ErrConnectionClosed
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+
+	select {
+	case err := <-result:
+		return true, err
+	case <-runDone:
+		// Run may have stopped (Leave, a fatal error) before getting to the switch.  it could also have finished it just before, in which case its result is already waiting
+		select {
+		case err := <-result:
+			return true, err
+		default:
+			return true, ErrConnectionClosed
+		}
+	case <-ctx.Done():
+		return true, ctx.Err()
+	}
+}
+
+// BreakoutPolicy controls what the session does by itself when the host uses breakout rooms, see WithBreakoutPolicy.  The zero value does nothing, which is the default.
+type BreakoutPolicy struct {
+	// join the room the host assigns us to (WS_CONF_BO_COMMAND_INDICATION with a token)
+	FollowAssignments bool
+	// go back to the main meeting when the host closes the rooms, at the end of the countdown zoom gives everyone
+	ReturnOnClose bool
+}
+
+// BreakoutRoomClosing is passed to the onMessage function (and OnBreakoutRoomClosing handlers) when the host closes the breakout rooms while we are in one.  like StateChange it is not sent by zoom
+type BreakoutRoomClosing struct {
+	WaitSeconds int // how long until everyone is sent back to the main meeting
+}
+
+// BreakoutRoomBroadcast is passed to the onMessage function (and OnBreakoutRoomBroadcast handlers) when the host broadcasts a message to all breakout rooms.  like StateChange it is not sent by zoom
+type BreakoutRoomBroadcast struct {
+	Text string
+}
+
+// ControlStatus the web client sends when opening rooms (see CreateBreakoutRoom).  anything else while we are in a room means they are being closed
+const breakoutRoomsStarted = 2
+
+// go back a little before zoom kicks everyone out at the end of the countdown, otherwise we would try to reconnect to the closed room
+const breakoutCloseMargin = 1 * time.Second
+
+// OnBreakoutRoomClosing registers a handler for the host closing breakout rooms while we are in one.  Call the returned function to remove it.
+func (session *ZoomSession) OnBreakoutRoomClosing(fn func(session *ZoomSession, closing *BreakoutRoomClosing) error) func() {
+	return session.handlers.add(reflect.TypeOf(BreakoutRoomClosing{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*BreakoutRoomClosing))
+	})
+}
+
+// OnBreakoutRoomBroadcast registers a handler for messages the host broadcasts to breakout rooms.  Call the returned function to remove it.
+func (session *ZoomSession) OnBreakoutRoomBroadcast(fn func(session *ZoomSession, broadcast *BreakoutRoomBroadcast) error) func() {
+	return session.handlers.add(reflect.TypeOf(BreakoutRoomBroadcast{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*BreakoutRoomBroadcast))
+	})
+}
+
+// handleBreakoutMessage is called by the reader for breakout room commands and attributes and acts on them according to the BreakoutPolicy
+func (session *ZoomSession) handleBreakoutMessage(onMessageFunction onMessage, message Message) {
+	switch m := message.(type) {
+	case *ConferenceBreakoutRoomCommandIndication:
+		if len(m.TextContent) > 0 {
+			session.dispatch(onMessageFunction, &BreakoutRoomBroadcast{Text: string(m.TextContent)})
+		}
+		if m.Botoken == "" || m.TargetBID == "" || !session.breakoutPolicy.FollowAssignments {
+			return
+		}
+		session.mu.Lock()
+		// zoom can send the same assignment several times, only move once
+		if m.TargetBID == session.breakoutBID || m.TargetBID == session.breakoutTarget {
+			session.mu.Unlock()
+			return
+		}
+		session.breakoutTarget = m.TargetBID
+		session.mu.Unlock()
+		session.logger.Info("Following breakout room assignment", "bid", m.TargetBID)
+		// the reader has to keep going while we switch so this can't block it
+		go func() {
+			err := session.moveToBreakoutRoom(context.Background(), m.TargetBID, m.Botoken)
+			session.mu.Lock()
+			if session.breakoutTarget == m.TargetBID {
+				session.breakoutTarget = ""
+			}
+			session.mu.Unlock()
+			if err != nil {
+				session.logger.Warn("Following breakout room assignment failed", "bid", m.TargetBID, "error", err)
+				session.reportError(err)
+			}
+		}()
+	case *ConferenceBreakoutRoomAttributeIndication:
+		if m.Proto.ControlStatus == breakoutRoomsStarted || session.BreakoutRoom() == "" {
+			return
+		}
+		session.dispatch(onMessageFunction, &BreakoutRoomClosing{WaitSeconds: m.Proto.WaitSeconds})
+		if session.breakoutPolicy.ReturnOnClose {
+			session.startBreakoutCloseTimer(time.Duration(m.Proto.WaitSeconds) * time.Second)
+		}
+	}
+}
+
+// startBreakoutCloseTimer returns us to the main meeting once the close countdown is (nearly) over
+func (session *ZoomSession) startBreakoutCloseTimer(wait time.Duration) {
+	wait -= breakoutCloseMargin
+	if wait < 0 {
+		wait = 0
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.breakoutCloseTimer != nil {
+		// zoom repeats the attributes, keep the countdown we already have
+		return
+	}
+	session.logger.Info("Breakout rooms closing", "bid", session.breakoutBID, "wait", wait)
+	session.breakoutCloseTimer = time.AfterFunc(wait, func() {
+		session.mu.Lock()
+		session.breakoutCloseTimer = nil
+		session.mu.Unlock()
+		if err := session.ReturnToMainSession(context.Background()); err != nil && err != ErrNotInBreakoutRoom {
+			session.logger.Warn("Returning to main session failed", "error", err)
+			session.reportError(err)
+		}
+	})
+}
+
+func (session *ZoomSession) stopBreakoutCloseTimer() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.breakoutCloseTimer != nil {
+		session.breakoutCloseTimer.Stop()
+		session.breakoutCloseTimer = nil
+	}
+}
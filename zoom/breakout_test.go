@@ -0,0 +1,118 @@
+package zoom_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+func TestJoinBreakoutRoomAndReturn(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+	runErr := startSession(t, session, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if err := session.JoinBreakoutRoom(ctx, "fakeBID"); err != nil {
+		t.Fatal(err)
+	}
+	waitForState(t, session, zoom.StateInBreakout)
+	if bid := session.BreakoutRoom(); bid != "fakeBID" {
+		t.Errorf("BreakoutRoom is %q after joining", bid)
+	}
+	if opt := server.Joins()[1].Get("opt"); opt != zoomtest.BreakoutToken("fakeBID") {
+		t.Errorf("Joined the breakout room with opt %q", opt)
+	}
+
+	if err := session.ReturnToMainSession(ctx); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, server, zoom.WS_CONF_BO_LEAVE_REQ)
+	waitForState(t, session, zoom.StateInMeeting)
+	if opt := server.Joins()[2].Get("opt"); opt != "" {
+		t.Errorf("Returned to the main meeting with opt %q", opt)
+	}
+	if err := session.ReturnToMainSession(ctx); err != zoom.ErrNotInBreakoutRoom {
+		t.Errorf("ReturnToMainSession in the main meeting returned %v", err)
+	}
+
+	leave(t, session, runErr)
+}
+
+func TestJoinBreakoutRoomFromHandler(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	// "++join <bid>" and "++return" chat commands
+	results := make(chan error, 1)
+	session.OnChat(func(session *zoom.ZoomSession, message *zoom.ConferenceChatIndication) error {
+		ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+		defer cancel()
+		words := strings.Fields(string(message.Text))
+		switch words[0] {
+		case "++join":
+			results <- session.JoinBreakoutRoom(ctx, words[1])
+		case "++return":
+			results <- session.ReturnToMainSession(ctx)
+		}
+		return nil
+	})
+	runErr := startSession(t, session, nil)
+
+	command := func(text string) {
+		t.Helper()
+		server.Send(zoom.WS_CONF_CHAT_INDICATION, zoom.ConferenceChatIndication{Text: []byte(text)})
+		select {
+		case err := <-results:
+			if err != nil {
+				t.Fatalf("%s: %v", text, err)
+			}
+		case <-time.After(testTimeout):
+			t.Fatalf("%s did not return", text)
+		}
+	}
+
+	command("++join fakeBID")
+	waitForState(t, session, zoom.StateInBreakout)
+	if opt := server.Joins()[1].Get("opt"); opt != zoomtest.BreakoutToken("fakeBID") {
+		t.Errorf("Joined the breakout room with opt %q", opt)
+	}
+
+	// handlers are still there in the breakout room
+	command("++return")
+	waitForState(t, session, zoom.StateInMeeting)
+	if session.BreakoutRoom() != "" {
+		t.Errorf("Still in breakout room %q", session.BreakoutRoom())
+	}
+
+	leave(t, session, runErr)
+}
+
+func TestFollowAssignmentOnce(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server, zoom.WithBreakoutPolicy(zoom.BreakoutPolicy{FollowAssignments: true}))
+	runErr := startSession(t, session, nil)
+
+	// zoom repeats assignments, the second one arrives while we are still moving
+	for i := 0; i < 2; i++ {
+		server.Send(zoom.WS_CONF_BO_COMMAND_INDICATION, zoom.ConferenceBreakoutRoomCommandIndication{
+			Botoken:   zoomtest.BreakoutToken("fakeBID"),
+			TargetBID: "fakeBID",
+		})
+	}
+	waitForState(t, session, zoom.StateInBreakout)
+	// give a second move time to show up
+	time.Sleep(100 * time.Millisecond)
+	if joins := len(server.Joins()); joins != 2 {
+		t.Errorf("Connected %d times, wanted 2", joins)
+	}
+
+	leave(t, session, runErr)
+}
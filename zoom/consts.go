@@ -1,26 +1,47 @@
 package zoom
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+)
 
+// UserAgent is the browser we pretend to be
 // make sure these are consistent
-// TODO: make configurable
+type UserAgent struct {
+	Header    string // sent as the User-Agent header
+	Shorthand string // sent as the "browser" websocket parameter
+}
+
+var DefaultUserAgent = UserAgent{
+	Header:    "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Safari/537.36",
+	Shorthand: "Chrome112", // todo: figure out zooms algorithm for determining this
+}
+
 const (
-	userAgent          = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/112.0.0.0 Safari/537.36"
-	userAgentShorthand = "Chrome112" // todo: figure out zooms algorithm for determining this
+	DefaultSDKVersion = "2.12.0"
+	DefaultLanguage   = "en-US"
 )
 
-func httpHeaders() http.Header {
+// acceptLanguage turns a language like "en-US" into an Accept-Language header like "en-US,en;q=0.9"
+func acceptLanguage(language string) string {
+	if base := strings.SplitN(language, "-", 2)[0]; base != language {
+		return language + "," + base + ";q=0.9"
+	}
+	return language
+}
+
+func (session *ZoomSession) httpHeaders() http.Header {
 	return http.Header{
 		http.CanonicalHeaderKey("pragma"):                    []string{"no-cache"},
 		http.CanonicalHeaderKey("cache-control"):             []string{"no-cache"},
 		http.CanonicalHeaderKey("upgrade-insecure-requests"): []string{"1"},
-		http.CanonicalHeaderKey("user-agent"):                []string{userAgent},
+		http.CanonicalHeaderKey("user-agent"):                []string{session.userAgent.Header},
 		http.CanonicalHeaderKey("accept"):                    []string{"application/json, text/plain, */*"},
 		http.CanonicalHeaderKey("sec-fetch-site"):            []string{"none"},
 		http.CanonicalHeaderKey("sec-fetch-mode"):            []string{"navigate"},
 		http.CanonicalHeaderKey("sec-fetch-user"):            []string{"?1"},
 		http.CanonicalHeaderKey("sec-fetch-dest"):            []string{"document"},
-		http.CanonicalHeaderKey("accept-language"):           []string{"en-US,en;q=0.9"},
+		http.CanonicalHeaderKey("accept-language"):           []string{acceptLanguage(session.language)},
 	}
 }
 
@@ -41,8 +62,8 @@ const (
 	WS_CONF_LOCK_RES                                 = 4100
 	WS_CONF_END_REQ                                  = 4101 // ConferenceEndRequest
 	WS_CONF_END_RES                                  = 4102
-	WS_CONF_LEAVE_REQ                                = 4103
-	WS_CONF_LEAVE_RES                                = 4104
+	WS_CONF_LEAVE_REQ                                = 4103 // ConferenceLeaveRequest
+	WS_CONF_LEAVE_RES                                = 4104 // ConferenceLeaveResponse
 	WS_CONF_RECORD_REQ                               = 4105
 	WS_CONF_RECORD_RES                               = 4106
 	WS_CONF_EXPEL_REQ                                = 4107
@@ -80,7 +101,7 @@ const (
 	WS_CONF_BO_ASSIGN_REQ                            = 4179
 	WS_CONF_BO_SWITCH_REQ                            = 4181
 	WS_CONF_BO_WANT_JOIN_REQ                         = 4183
-	WS_CONF_BO_LEAVE_REQ                             = 4185
+	WS_CONF_BO_LEAVE_REQ                             = 4185 // ConferenceBreakoutRoomLeaveRequest
 	WS_CONF_BO_BROADCAST_REQ                         = 4187 // ConferenceBreakoutRoomBroadcastRequest
 	WS_CONF_BO_HELP_REQ                              = 4189
 	WS_CONF_BO_HELP_RESULT_REQ                       = 4191
@@ -97,8 +118,8 @@ const (
 	WS_CONF_EXPEL_ATTENDEE_RES                       = 4206
 	WS_CONF_PRACTICE_SESSION_REQ                     = 4207
 	WS_CONF_PRACTICE_SESSION_RES                     = 4208
-	WS_CONF_ROLE_CHANGE_REQ                          = 4209
-	WS_CONF_ROLE_CHANGE_RES                          = 4210
+	WS_CONF_ROLE_CHANGE_REQ                          = 4209 // ConferenceRoleChangeRequest
+	WS_CONF_ROLE_CHANGE_RES                          = 4210 // ConferenceRoleChangeResponse
 	WS_CONF_BO_TOKEN_BATCH_REQ                       = 4211 // ConferenceBreakoutRoomTokenBatchRequest
 	WS_CONF_BO_PRE_ASSIGN_REQ                        = 4213
 	WS_CONF_BO_PRE_ASSIGN_RES                        = 4214
@@ -119,8 +140,8 @@ const (
 	WS_AUDIO_CANCEL_DIALOUT_RES                      = 8200
 	WS_AUDIO_MUTEALL_REQ                             = 8201 // AudioMuteAllRequest
 	WS_AUDIO_MUTEALL_RES                             = 8202
-	WS_AUDIO_ALLOW_TALK_REQ                          = 8204
-	WS_AUDIO_ALLOW_TALK_RES                          = 8205
+	WS_AUDIO_ALLOW_TALK_REQ                          = 8204 // AudioAllowTalkRequest
+	WS_AUDIO_ALLOW_TALK_RES                          = 8205 // AudioAllowTalkResponse
 	WS_CONF_ROSTER_INDICATION                        = 7937 // ConferenceRosterIndication
 	WS_CONF_ATTRIBUTE_INDICATION                     = 7938 // ConferenceAttributeIndication
 	WS_CONF_END_INDICATION                           = 7939
@@ -144,9 +165,9 @@ const (
 	WS_AUDIO_ASN_INDICATION                          = 12033 // AudioAsnIndication
 	WS_AUDIO_MUTE_INDICATION                         = 12034
 	WS_AUDIO_SSRC_INDICATION                         = 12035 // AudioSSRCIndication
-	WS_AUDIO_ALLOW_TALK_INDICATION                   = 12036
+	WS_AUDIO_ALLOW_TALK_INDICATION                   = 12036 // AudioAllowTalkIndication
 	WS_AUDIO_SSRC_ASK_UNMUTE_INDICATION              = 12037
-	WS_WEBINAR_VIEW_ONLY_TELEPHONY_INDICATION        = 12038
+	WS_WEBINAR_VIEW_ONLY_TELEPHONY_INDICATION        = 12038 // WebinarViewOnlyTelephonyIndication
 	WS_VIDEO_ACTIVE_INDICATION                       = 16129 // VideoActiveIndication
 	WS_VIDEO_SSRC_INDICATION                         = 16131 // SSRCIndication
 	WS_VIDEO_MUTE_INDICATION                         = 16133
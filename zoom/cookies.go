@@ -0,0 +1,128 @@
+package zoom
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// SavedCookie is a cookie as zoom set it, with the url of the response that set it
+type SavedCookie struct {
+	URL    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+// cookieRecorder wraps the session's jar and remembers every cookie set through it.  jars only give back names and values, which isn't enough to put the cookies back in a new jar for the right hosts and paths (see Snapshot)
+type cookieRecorder struct {
+	http.CookieJar
+
+	mu      sync.Mutex
+	cookies map[string]SavedCookie // keyed by host, domain, path and name, like the jar itself
+}
+
+func (jar *cookieRecorder) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	jar.CookieJar.SetCookies(u, cookies)
+
+	jar.mu.Lock()
+	defer jar.mu.Unlock()
+	if jar.cookies == nil {
+		jar.cookies = make(map[string]SavedCookie)
+	}
+	origin := url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}
+	for _, cookie := range cookies {
+		copied := *cookie
+		// Max-Age counts from now, so turn it into an expiry that still means the same thing when restored
+		if copied.MaxAge > 0 {
+			copied.Expires = time.Now().Add(time.Duration(copied.MaxAge) * time.Second)
+			copied.MaxAge = 0
+		}
+		key := u.Hostname() + ";" + copied.Domain + ";" + copied.Path + ";" + copied.Name
+		if copied.MaxAge < 0 {
+			delete(jar.cookies, key)
+			continue
+		}
+		jar.cookies[key] = SavedCookie{URL: origin.String(), Cookie: &copied}
+	}
+}
+
+// saved returns the cookies that haven't expired
+func (jar *cookieRecorder) saved() []SavedCookie {
+	jar.mu.Lock()
+	defer jar.mu.Unlock()
+	now := time.Now()
+	saved := make([]SavedCookie, 0, len(jar.cookies))
+	for key, cookie := range jar.cookies {
+		if !cookie.Cookie.Expires.IsZero() && cookie.Cookie.Expires.Before(now) {
+			delete(jar.cookies, key)
+			continue
+		}
+		saved = append(saved, cookie)
+	}
+	return saved
+}
+
+// restore puts saved cookies back into the jar for the urls they came from
+func (jar *cookieRecorder) restore(saved []SavedCookie) {
+	for _, cookie := range saved {
+		u, err := url.Parse(cookie.URL)
+		if err != nil || cookie.Cookie == nil {
+			continue
+		}
+		jar.SetCookies(u, []*http.Cookie{cookie.Cookie})
+	}
+}
+
+// CookieJar returns the jar holding the cookies zoom has set.  It is used for the meeting info request, the RWG ping and the websocket.
+func (session *ZoomSession) CookieJar() http.CookieJar {
+	return session.cookieJar
+}
+
+// Cookies returns the cookies that would be sent with the meeting info request
+func (session *ZoomSession) Cookies() []*http.Cookie {
+	infoURL, err := url.Parse(session.Endpoints.Info)
+	if err != nil {
+		return nil
+	}
+	return session.cookieJar.Cookies(infoURL)
+}
+
+// SetCookies adds cookies for the meeting info endpoint, for example ones saved from Cookies by an earlier run
+func (session *ZoomSession) SetCookies(cookies []*http.Cookie) {
+	infoURL, err := url.Parse(session.Endpoints.Info)
+	if err != nil {
+		return
+	}
+	scoped := make([]*http.Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		// Cookies doesn't give us the path, and without one the jar would only send them to the info endpoint's directory
+		if cookie.Path == "" {
+			copied := *cookie
+			copied.Path = "/"
+			cookie = &copied
+		}
+		scoped = append(scoped, cookie)
+	}
+	session.cookieJar.SetCookies(infoURL, scoped)
+}
+
+// setWebsocketCookies adds cookies formatted for a Cookie header to the jar, for every path on websocketUrl's host
+func (session *ZoomSession) setWebsocketCookies(websocketUrl string, cookieString string) error {
+	parsed, err := url.Parse(websocketUrl)
+	if err != nil {
+		return err
+	}
+	// the jar only handles http urls, gorilla converts the scheme the same way when it looks cookies up
+	switch parsed.Scheme {
+	case "ws":
+		parsed.Scheme = "http"
+	case "wss":
+		parsed.Scheme = "https"
+	}
+	cookies := (&http.Request{Header: http.Header{"Cookie": []string{cookieString}}}).Cookies()
+	for _, cookie := range cookies {
+		cookie.Path = "/"
+	}
+	session.cookieJar.SetCookies(parsed, cookies)
+	return nil
+}
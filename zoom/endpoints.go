@@ -0,0 +1,21 @@
+package zoom
+
+// Endpoints are the zoom web services a session talks to.  Change them to use a regional zoom domain, go through a recording proxy or point at a local stand-in like zoomtest.Server.
+type Endpoints struct {
+	// JSONP meeting info endpoint
+	Info string
+	// scheme used for the RWG ping request ("https" or "http")
+	PingScheme string
+	// scheme used for the meeting websocket ("wss" or "ws")
+	WebsocketScheme string
+	// if set, used instead of the RWG hosts returned in the meeting info
+	RwgHost string
+}
+
+func DefaultEndpoints() Endpoints {
+	return Endpoints{
+		Info:            "https://zoom.us/api/v1/wc/info",
+		PingScheme:      "https",
+		WebsocketScheme: "wss",
+	}
+}
@@ -0,0 +1,148 @@
+package zoom
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// reasons the meeting info request (and so Connect) can fail.  test for them with errors.Is
+var (
+	ErrMeetingNotFound      = errors.New("Meeting does not exist")
+	ErrWrongPassword        = errors.New("Wrong meeting password")
+	ErrMeetingNotStarted    = errors.New("Meeting has not started")
+	ErrSignatureInvalid     = errors.New("Invalid signature")
+	ErrCaptchaRequired      = errors.New("Captcha required")
+	ErrMeetingLocked        = errors.New("Meeting is locked")
+	ErrRegistrationRequired = errors.New("Meeting requires registration")
+	ErrLoginRequired        = errors.New("Meeting requires signing in")
+	ErrEmailRequired        = errors.New("Meeting requires an email address")
+)
+
+// errorCode values from /wc/info that we know the meaning of
+var meetingInfoErrorCodes = map[int]error{
+	3001: ErrMeetingNotFound,
+	3004: ErrWrongPassword,
+	3008: ErrMeetingNotStarted,
+	3706: ErrMeetingNotFound, // meeting number is wrong
+	3712: ErrSignatureInvalid,
+}
+
+// zoom adds codes over time so fall back to the error message for ones that are not in the table above
+var meetingInfoErrorMessages = []struct {
+	substring string
+	err       error
+}{
+	{"captcha", ErrCaptchaRequired},
+	{"password", ErrWrongPassword},
+	{"passcode", ErrWrongPassword},
+	{"not started", ErrMeetingNotStarted},
+	{"not exist", ErrMeetingNotFound},
+	{"signature", ErrSignatureInvalid},
+	{"locked", ErrMeetingLocked},
+	{"regist", ErrRegistrationRequired},
+	{"sign in", ErrLoginRequired},
+	{"login", ErrLoginRequired},
+	{"email", ErrEmailRequired},
+}
+
+// MeetingInfoError is returned when zoom refuses the meeting info request.  errors.Is works with the Err* variables above when we know what Code means
+type MeetingInfoError struct {
+	Code    int
+	Message string
+	Err     error  // nil if we don't recognize the code
+	Hint    string // what to do about it, if it is something we can help with
+}
+
+func newMeetingInfoError(meetingInfo *MeetingInfo) *MeetingInfoError {
+	infoErr := &MeetingInfoError{
+		Code:    meetingInfo.ErrorCode,
+		Message: meetingInfo.ErrorMessage,
+		Err:     meetingInfoErrorCodes[meetingInfo.ErrorCode],
+	}
+	if infoErr.Err == nil {
+		message := strings.ToLower(meetingInfo.ErrorMessage)
+		for _, m := range meetingInfoErrorMessages {
+			if strings.Contains(message, m.substring) {
+				infoErr.Err = m.err
+				break
+			}
+		}
+	}
+	return infoErr
+}
+
+func (e *MeetingInfoError) Error() string {
+	message := fmt.Sprintf("Failed to get meeting info: error code %d: %s", e.Code, e.Message)
+	if e.Err != nil {
+		message = fmt.Sprintf("Failed to get meeting info: %v (error code %d: %s)", e.Err, e.Code, e.Message)
+	}
+	if e.Hint != "" {
+		message += ".  " + e.Hint
+	}
+	return message
+}
+
+func (e *MeetingInfoError) Unwrap() error {
+	return e.Err
+}
+
+// ErrUnknownMessage is wrapped by a DecodeError for messages that have no type in msgTypes (see message.go).  zoom sends plenty of these so you may want to filter them out with errors.Is
+var ErrUnknownMessage = errors.New("Missing type definition in zoom/message.go")
+
+// DecodeError is reported when a message from zoom can't be turned into its go type, usually because zoom changed the payload
+type DecodeError struct {
+	Evt int
+	Seq uint32
+	Raw json.RawMessage
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("Failed to decode %s (evt %d, seq %d): %v", MessageNumberToName[e.Evt], e.Evt, e.Seq, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// HandlerError is reported when the onMessage function or a handler returns an error
+type HandlerError struct {
+	Evt     int
+	Seq     uint32
+	Message Message
+	Err     error
+}
+
+func (e *HandlerError) Error() string {
+	return fmt.Sprintf("Handler for %s (evt %d, seq %d) failed: %v", MessageNumberToName[e.Evt], e.Evt, e.Seq, e.Err)
+}
+
+func (e *HandlerError) Unwrap() error {
+	return e.Err
+}
+
+// errors are passed to OnError handlers wrapped in this so they can go through the handler registry without being mistaken for messages
+type errorEvent struct {
+	err error
+}
+
+// OnError registers a handler for errors that don't stop the session, like *DecodeError and *HandlerError.  Call the returned function to remove it.
+// see WithAbortOnHandlerError to stop the session on handler errors instead
+func (session *ZoomSession) OnError(fn func(session *ZoomSession, err error)) func() {
+	return session.handlers.add(reflect.TypeOf(errorEvent{}), func(session *ZoomSession, message Message) error {
+		fn(session, message.(*errorEvent).err)
+		return nil
+	})
+}
+
+func (session *ZoomSession) reportError(err error) {
+	event := &errorEvent{err: err}
+	session.runHandler(func() {
+		for _, h := range session.handlers.get(reflect.TypeOf(errorEvent{})) {
+			h.fn(session, event)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package zoom_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+func TestDecodeErrorKeepsConnection(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	decodeErrors := make(chan *zoom.DecodeError, 10)
+	session.OnError(func(session *zoom.ZoomSession, err error) {
+		var decodeErr *zoom.DecodeError
+		if errors.As(err, &decodeErr) {
+			decodeErrors <- decodeErr
+		}
+	})
+	chats := make(chan struct{}, 1)
+	session.OnChat(func(session *zoom.ZoomSession, message *zoom.ConferenceChatIndication) error {
+		chats <- struct{}{}
+		return nil
+	})
+	runErr := startSession(t, session, nil)
+
+	// zoom changes the payloads the reader depends on
+	for _, evt := range []int{zoom.WS_CONF_JOIN_RES, zoom.WS_CONF_HOLD_CHANGE_INDICATION} {
+		server.Send(evt, json.RawMessage(`{"role":"host","bHold":"yes"}`))
+		select {
+		case decodeErr := <-decodeErrors:
+			if decodeErr.Evt != evt {
+				t.Errorf("Got decode error for %s, wanted %s", zoom.MessageNumberToName[decodeErr.Evt], zoom.MessageNumberToName[evt])
+			}
+		case <-time.After(testTimeout):
+			t.Fatalf("No decode error for %s", zoom.MessageNumberToName[evt])
+		}
+	}
+
+	// still connected and handling messages
+	server.Send(zoom.WS_CONF_CHAT_INDICATION, zoom.ConferenceChatIndication{Text: []byte("hello")})
+	select {
+	case <-chats:
+	case <-time.After(testTimeout):
+		t.Fatal("Chat after decode errors was not handled")
+	}
+	if joins := len(server.Joins()); joins != 1 {
+		t.Errorf("Connected %d times", joins)
+	}
+
+	leave(t, session, runErr)
+}
+
+func TestAbortOnHandlerError(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server, zoom.WithAbortOnHandlerError(true))
+
+	failure := errors.New("handler failed")
+	session.OnChat(func(session *zoom.ZoomSession, message *zoom.ConferenceChatIndication) error {
+		return failure
+	})
+	runErr := startSession(t, session, nil)
+
+	server.Send(zoom.WS_CONF_CHAT_INDICATION, zoom.ConferenceChatIndication{Text: []byte("hello")})
+	err := waitForRun(t, runErr)
+	var handlerErr *zoom.HandlerError
+	if !errors.As(err, &handlerErr) || !errors.Is(err, failure) {
+		t.Fatalf("Run returned %v, wanted the handler's error", err)
+	}
+	if handlerErr.Evt != zoom.WS_CONF_CHAT_INDICATION {
+		t.Errorf("HandlerError is for %s", zoom.MessageNumberToName[handlerErr.Evt])
+	}
+	waitFor(t, server, zoom.WS_CONF_LEAVE_REQ)
+}
@@ -0,0 +1,69 @@
+//go:build ignore
+// +build ignore
+
+// generates handlers_gen.go, which has an On<Type> method for every message type in the msgTypes table in message.go
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+func main() {
+	fileSet := token.NewFileSet()
+	file, err := parser.ParseFile(fileSet, "message.go", nil, 0)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// type name -> message names using that type, in the order they appear in msgTypes
+	var typeNames []string
+	messageNames := make(map[string][]string)
+	ast.Inspect(file, func(node ast.Node) bool {
+		valueSpec, ok := node.(*ast.ValueSpec)
+		if !ok || len(valueSpec.Names) != 1 || valueSpec.Names[0].Name != "msgTypes" {
+			return true
+		}
+		for _, element := range valueSpec.Values[0].(*ast.CompositeLit).Elts {
+			keyValue := element.(*ast.KeyValueExpr)
+			// reflect.TypeOf(Type{})
+			typeName := keyValue.Value.(*ast.CallExpr).Args[0].(*ast.CompositeLit).Type.(*ast.Ident).Name
+			if _, ok := messageNames[typeName]; !ok {
+				typeNames = append(typeNames, typeName)
+			}
+			messageNames[typeName] = append(messageNames[typeName], keyValue.Key.(*ast.Ident).Name)
+		}
+		return false
+	})
+	if len(typeNames) == 0 {
+		log.Fatal("msgTypes not found in message.go")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by generate_handlers.go; DO NOT EDIT.\n\npackage zoom\n\nimport \"reflect\"\n")
+	for _, typeName := range typeNames {
+		fmt.Fprintf(&buf, `
+// On%[1]s registers a handler for %[2]s messages.  Call the returned function to remove it.
+func (session *ZoomSession) On%[1]s(fn func(session *ZoomSession, message *%[1]s) error) func() {
+	return session.handlers.add(reflect.TypeOf(%[1]s{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*%[1]s))
+	})
+}
+`, typeName, strings.Join(messageNames[typeName], "/"))
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("handlers_gen.go", source, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,229 @@
+package zoom
+
+import (
+	"reflect"
+	"sync"
+)
+
+//go:generate go run generate_handlers.go
+
+type handler func(session *ZoomSession, message Message) error
+
+// handlers added with OnMessage are stored under the Message interface type since no concrete message type will ever match it
+var anyMessageType = reflect.TypeOf((*Message)(nil)).Elem()
+
+type registeredHandler struct {
+	id uint64
+	fn handler
+}
+
+// handlerRegistry holds the handlers added with the On* methods, keyed by the (non pointer) type of the message they accept.
+// handlers for a type run in the order they were added, after the onMessage function passed to Run
+type handlerRegistry struct {
+	mu       sync.RWMutex
+	nextID   uint64
+	handlers map[reflect.Type][]registeredHandler
+}
+
+func (registry *handlerRegistry) add(typ reflect.Type, fn handler) func() {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if registry.handlers == nil {
+		registry.handlers = make(map[reflect.Type][]registeredHandler)
+	}
+	registry.nextID++
+	id := registry.nextID
+	registry.handlers[typ] = append(registry.handlers[typ], registeredHandler{id: id, fn: fn})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			registry.remove(typ, id)
+		})
+	}
+}
+
+func (registry *handlerRegistry) remove(typ reflect.Type, id uint64) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	handlers := registry.handlers[typ]
+	for i, h := range handlers {
+		if h.id == id {
+			// copy instead of modifying in place so a dispatch already in progress keeps its snapshot intact
+			updated := make([]registeredHandler, 0, len(handlers)-1)
+			updated = append(updated, handlers[:i]...)
+			registry.handlers[typ] = append(updated, handlers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (registry *handlerRegistry) get(typ reflect.Type) []registeredHandler {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	return registry.handlers[typ]
+}
+
+// dispatcher runs handlers on a goroutine of their own while Run is running.  this keeps the reader going while a handler waits on the connection (SendAndWait and everything built on it), since the reader is what delivers the response.
+// handlers still run one at a time, in the order messages arrived
+type dispatcher struct {
+	mu     sync.Mutex
+	queue  []func()
+	closed bool
+	wake   chan struct{}
+	done   chan struct{}
+}
+
+func newDispatcher() *dispatcher {
+	d := &dispatcher{
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// add queues fn, returning false if the dispatcher has been stopped.  it never blocks, however far behind the handlers are
+func (d *dispatcher) add(fn func()) bool {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return false
+	}
+	d.queue = append(d.queue, fn)
+	d.mu.Unlock()
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+func (d *dispatcher) run() {
+	defer close(d.done)
+	for {
+		d.mu.Lock()
+		queue, closed := d.queue, d.closed
+		d.queue = nil
+		d.mu.Unlock()
+
+		for _, fn := range queue {
+			fn()
+		}
+		if len(queue) == 0 {
+			if closed {
+				return
+			}
+			<-d.wake
+		}
+	}
+}
+
+// stop runs whatever is still queued and waits for it to finish
+func (d *dispatcher) stop() {
+	d.mu.Lock()
+	d.closed = true
+	d.mu.Unlock()
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	<-d.done
+}
+
+// runHandler runs fn on the dispatcher if Run is running, otherwise straight away
+func (session *ZoomSession) runHandler(fn func()) {
+	session.mu.Lock()
+	dispatcher := session.dispatcher
+	session.mu.Unlock()
+	if dispatcher == nil || !dispatcher.add(fn) {
+		fn()
+	}
+}
+
+// dispatch passes a message to the handlers (see callHandlers).  it is used for the events we make up ourselves, like StateChange, so errors from the handlers are ignored
+func (session *ZoomSession) dispatch(onMessageFunction onMessage, message Message) {
+	session.runHandler(func() {
+		session.callHandlers(onMessageFunction, message)
+	})
+}
+
+// callHandlers passes a message to onMessageFunction (if any), then to the OnMessage handlers and finally to the handlers registered for its type.
+// it keeps going if a handler fails and returns the first error
+func (session *ZoomSession) callHandlers(onMessageFunction onMessage, message Message) error {
+	var firstErr error
+	if onMessageFunction != nil {
+		firstErr = onMessageFunction(session, message)
+	}
+
+	handlers := session.handlers.get(anyMessageType)
+	if typ := reflect.TypeOf(message); typ != nil {
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		handlers = append(handlers[:len(handlers):len(handlers)], session.handlers.get(typ)...)
+	}
+	for _, h := range handlers {
+		if err := h.fn(session, message); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// OnMessage registers a handler for every message, the same way the onMessage function passed to Run is called.  Call the returned function to remove it.
+func (session *ZoomSession) OnMessage(fn func(session *ZoomSession, message Message) error) func() {
+	return session.handlers.add(anyMessageType, fn)
+}
+
+/*
+convenience handlers for common events.  hooks for every message type in msgTypes are in handlers_gen.go
+*/
+
+// OnChat registers a handler for chat messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnChat(fn func(session *ZoomSession, message *ConferenceChatIndication) error) func() {
+	return session.OnConferenceChatIndication(fn)
+}
+
+// OnHostChange registers a handler for host changes.  Call the returned function to remove it.
+func (session *ZoomSession) OnHostChange(fn func(session *ZoomSession, message *ConferenceHostChangeIndication) error) func() {
+	return session.OnConferenceHostChangeIndication(fn)
+}
+
+// OnRosterAdd registers a handler that is called once for every participant that joins.  Call the returned function to remove it.
+func (session *ZoomSession) OnRosterAdd(fn func(session *ZoomSession, person *RosterAddItem) error) func() {
+	return session.OnConferenceRosterIndication(func(session *ZoomSession, message *ConferenceRosterIndication) error {
+		var firstErr error
+		for i := range message.Add {
+			if err := fn(session, &message.Add[i]); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	})
+}
+
+// OnDisconnected registers a handler for unexpected disconnections.  Call the returned function to remove it.
+func (session *ZoomSession) OnDisconnected(fn func(session *ZoomSession, message *Disconnected) error) func() {
+	return session.handlers.add(reflect.TypeOf(Disconnected{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*Disconnected))
+	})
+}
+
+// OnReconnecting registers a handler that is called before every reconnection attempt.  Call the returned function to remove it.
+func (session *ZoomSession) OnReconnecting(fn func(session *ZoomSession, message *Reconnecting) error) func() {
+	return session.handlers.add(reflect.TypeOf(Reconnecting{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*Reconnecting))
+	})
+}
+
+// OnReconnected registers a handler for successful reconnections.  Call the returned function to remove it.
+func (session *ZoomSession) OnReconnected(fn func(session *ZoomSession, message *Reconnected) error) func() {
+	return session.handlers.add(reflect.TypeOf(Reconnected{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*Reconnected))
+	})
+}
@@ -0,0 +1,334 @@
+// Code generated by generate_handlers.go; DO NOT EDIT.
+
+package zoom
+
+import "reflect"
+
+// OnWebsocketConnectionKeepalive registers a handler for WS_CONN_KEEPALIVE messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnWebsocketConnectionKeepalive(fn func(session *ZoomSession, message *WebsocketConnectionKeepalive) error) func() {
+	return session.handlers.add(reflect.TypeOf(WebsocketConnectionKeepalive{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*WebsocketConnectionKeepalive))
+	})
+}
+
+// OnJoinConferenceResponse registers a handler for WS_CONF_JOIN_RES messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnJoinConferenceResponse(fn func(session *ZoomSession, message *JoinConferenceResponse) error) func() {
+	return session.handlers.add(reflect.TypeOf(JoinConferenceResponse{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*JoinConferenceResponse))
+	})
+}
+
+// OnConferenceChatIndication registers a handler for WS_CONF_CHAT_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceChatIndication(fn func(session *ZoomSession, message *ConferenceChatIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceChatIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceChatIndication))
+	})
+}
+
+// OnConferenceChatRequest registers a handler for WS_CONF_CHAT_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceChatRequest(fn func(session *ZoomSession, message *ConferenceChatRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceChatRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceChatRequest))
+	})
+}
+
+// OnConferenceAttributeIndication registers a handler for WS_CONF_ATTRIBUTE_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceAttributeIndication(fn func(session *ZoomSession, message *ConferenceAttributeIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceAttributeIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceAttributeIndication))
+	})
+}
+
+// OnConferenceRosterIndication registers a handler for WS_CONF_ROSTER_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceRosterIndication(fn func(session *ZoomSession, message *ConferenceRosterIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceRosterIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceRosterIndication))
+	})
+}
+
+// OnAudioVoipJoinChannelRequest registers a handler for WS_AUDIO_VOIP_JOIN_CHANNEL_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnAudioVoipJoinChannelRequest(fn func(session *ZoomSession, message *AudioVoipJoinChannelRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(AudioVoipJoinChannelRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*AudioVoipJoinChannelRequest))
+	})
+}
+
+// OnAudioMuteRequest registers a handler for WS_AUDIO_MUTE_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnAudioMuteRequest(fn func(session *ZoomSession, message *AudioMuteRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(AudioMuteRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*AudioMuteRequest))
+	})
+}
+
+// OnSetShareStatusRequest registers a handler for WS_CONF_SET_SHARE_STATUS_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnSetShareStatusRequest(fn func(session *ZoomSession, message *SetShareStatusRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(SetShareStatusRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*SetShareStatusRequest))
+	})
+}
+
+// OnConferenceRenameRequest registers a handler for WS_CONF_RENAME_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceRenameRequest(fn func(session *ZoomSession, message *ConferenceRenameRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceRenameRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceRenameRequest))
+	})
+}
+
+// OnAudioMuteAllRequest registers a handler for WS_AUDIO_MUTEALL_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnAudioMuteAllRequest(fn func(session *ZoomSession, message *AudioMuteAllRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(AudioMuteAllRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*AudioMuteAllRequest))
+	})
+}
+
+// OnConferenceSetMuteUponEntryRequest registers a handler for WS_CONF_SET_MUTE_UPON_ENTRY_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceSetMuteUponEntryRequest(fn func(session *ZoomSession, message *ConferenceSetMuteUponEntryRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceSetMuteUponEntryRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceSetMuteUponEntryRequest))
+	})
+}
+
+// OnConferenceAllowUnmuteAudioRequest registers a handler for WS_CONF_ALLOW_UNMUTE_AUDIO_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceAllowUnmuteAudioRequest(fn func(session *ZoomSession, message *ConferenceAllowUnmuteAudioRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceAllowUnmuteAudioRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceAllowUnmuteAudioRequest))
+	})
+}
+
+// OnConferenceAllowParticipantRenameRequest registers a handler for WS_CONF_ALLOW_PARTICIPANT_RENAME_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceAllowParticipantRenameRequest(fn func(session *ZoomSession, message *ConferenceAllowParticipantRenameRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceAllowParticipantRenameRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceAllowParticipantRenameRequest))
+	})
+}
+
+// OnConferenceAllowUnmuteVideoRequest registers a handler for WS_CONF_ALLOW_UNMUTE_VIDEO_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceAllowUnmuteVideoRequest(fn func(session *ZoomSession, message *ConferenceAllowUnmuteVideoRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceAllowUnmuteVideoRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceAllowUnmuteVideoRequest))
+	})
+}
+
+// OnConferenceChatPrivilegeRequest registers a handler for WS_CONF_CHAT_PRIVILEDGE_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceChatPrivilegeRequest(fn func(session *ZoomSession, message *ConferenceChatPrivilegeRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceChatPrivilegeRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceChatPrivilegeRequest))
+	})
+}
+
+// OnConferenceAvatarPermissionChanged registers a handler for WS_CONF_AVATAR_PERMISSION_CHANGED messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceAvatarPermissionChanged(fn func(session *ZoomSession, message *ConferenceAvatarPermissionChanged) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceAvatarPermissionChanged{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceAvatarPermissionChanged))
+	})
+}
+
+// OnConferenceLockShareRequest registers a handler for WS_CONF_LOCK_SHARE_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceLockShareRequest(fn func(session *ZoomSession, message *ConferenceLockShareRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceLockShareRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceLockShareRequest))
+	})
+}
+
+// OnConferenceLocalRecordIndication registers a handler for WS_CONF_LOCAL_RECORD_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceLocalRecordIndication(fn func(session *ZoomSession, message *ConferenceLocalRecordIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceLocalRecordIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceLocalRecordIndication))
+	})
+}
+
+// OnConferenceOptionIndication registers a handler for WS_CONF_OPTION_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceOptionIndication(fn func(session *ZoomSession, message *ConferenceOptionIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceOptionIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceOptionIndication))
+	})
+}
+
+// OnConferenceDCRegionIndication registers a handler for WS_CONF_DC_REGION_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceDCRegionIndication(fn func(session *ZoomSession, message *ConferenceDCRegionIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceDCRegionIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceDCRegionIndication))
+	})
+}
+
+// OnSSRCIndication registers a handler for WS_AUDIO_SSRC_INDICATION/WS_VIDEO_SSRC_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnSSRCIndication(fn func(session *ZoomSession, message *SSRCIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(SSRCIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*SSRCIndication))
+	})
+}
+
+// OnVideoActiveIndication registers a handler for WS_VIDEO_ACTIVE_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnVideoActiveIndication(fn func(session *ZoomSession, message *VideoActiveIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(VideoActiveIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*VideoActiveIndication))
+	})
+}
+
+// OnSharingStatusIndication registers a handler for WS_SHARING_STATUS_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnSharingStatusIndication(fn func(session *ZoomSession, message *SharingStatusIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(SharingStatusIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*SharingStatusIndication))
+	})
+}
+
+// OnConferenceCohostChangeIndication registers a handler for WS_CONF_COHOST_CHANGE_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceCohostChangeIndication(fn func(session *ZoomSession, message *ConferenceCohostChangeIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceCohostChangeIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceCohostChangeIndication))
+	})
+}
+
+// OnAudioAsnIndication registers a handler for WS_AUDIO_ASN_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnAudioAsnIndication(fn func(session *ZoomSession, message *AudioAsnIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(AudioAsnIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*AudioAsnIndication))
+	})
+}
+
+// OnConferenceBreakoutRoomAttributeIndication registers a handler for WS_CONF_BO_ATTRIBUTE_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceBreakoutRoomAttributeIndication(fn func(session *ZoomSession, message *ConferenceBreakoutRoomAttributeIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceBreakoutRoomAttributeIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceBreakoutRoomAttributeIndication))
+	})
+}
+
+// OnConferenceBreakoutRoomCommandIndication registers a handler for WS_CONF_BO_COMMAND_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceBreakoutRoomCommandIndication(fn func(session *ZoomSession, message *ConferenceBreakoutRoomCommandIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceBreakoutRoomCommandIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceBreakoutRoomCommandIndication))
+	})
+}
+
+// OnConferenceBreakoutRoomStartRequest registers a handler for WS_CONF_BO_START_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceBreakoutRoomStartRequest(fn func(session *ZoomSession, message *ConferenceBreakoutRoomStartRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceBreakoutRoomStartRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceBreakoutRoomStartRequest))
+	})
+}
+
+// OnConferenceBreakoutRoomBroadcastRequest registers a handler for WS_CONF_BO_BROADCAST_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceBreakoutRoomBroadcastRequest(fn func(session *ZoomSession, message *ConferenceBreakoutRoomBroadcastRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceBreakoutRoomBroadcastRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceBreakoutRoomBroadcastRequest))
+	})
+}
+
+// OnConferenceBreakoutRoomJoinRequest registers a handler for WS_CONF_BO_JOIN_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceBreakoutRoomJoinRequest(fn func(session *ZoomSession, message *ConferenceBreakoutRoomJoinRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceBreakoutRoomJoinRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceBreakoutRoomJoinRequest))
+	})
+}
+
+// OnConferenceBreakoutRoomJoinResponse registers a handler for WS_CONF_BO_JOIN_RES messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceBreakoutRoomJoinResponse(fn func(session *ZoomSession, message *ConferenceBreakoutRoomJoinResponse) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceBreakoutRoomJoinResponse{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceBreakoutRoomJoinResponse))
+	})
+}
+
+// OnConferenceBreakoutRoomLeaveRequest registers a handler for WS_CONF_BO_LEAVE_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceBreakoutRoomLeaveRequest(fn func(session *ZoomSession, message *ConferenceBreakoutRoomLeaveRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceBreakoutRoomLeaveRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceBreakoutRoomLeaveRequest))
+	})
+}
+
+// OnConferenceEndRequest registers a handler for WS_CONF_END_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceEndRequest(fn func(session *ZoomSession, message *ConferenceEndRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceEndRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceEndRequest))
+	})
+}
+
+// OnConferenceLeaveRequest registers a handler for WS_CONF_LEAVE_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceLeaveRequest(fn func(session *ZoomSession, message *ConferenceLeaveRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceLeaveRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceLeaveRequest))
+	})
+}
+
+// OnConferenceLeaveResponse registers a handler for WS_CONF_LEAVE_RES messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceLeaveResponse(fn func(session *ZoomSession, message *ConferenceLeaveResponse) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceLeaveResponse{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceLeaveResponse))
+	})
+}
+
+// OnConferenceBreakoutRoomTokenBatchRequest registers a handler for WS_CONF_BO_TOKEN_BATCH_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceBreakoutRoomTokenBatchRequest(fn func(session *ZoomSession, message *ConferenceBreakoutRoomTokenBatchRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceBreakoutRoomTokenBatchRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceBreakoutRoomTokenBatchRequest))
+	})
+}
+
+// OnConferenceBreakoutRoomTokenResponse registers a handler for WS_CONF_BO_TOKEN_RES messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceBreakoutRoomTokenResponse(fn func(session *ZoomSession, message *ConferenceBreakoutRoomTokenResponse) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceBreakoutRoomTokenResponse{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceBreakoutRoomTokenResponse))
+	})
+}
+
+// OnConferenceHostChangeIndication registers a handler for WS_CONF_HOST_CHANGE_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceHostChangeIndication(fn func(session *ZoomSession, message *ConferenceHostChangeIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceHostChangeIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceHostChangeIndication))
+	})
+}
+
+// OnConferenceEndIndication registers a handler for WS_CONF_END_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceEndIndication(fn func(session *ZoomSession, message *ConferenceEndIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceEndIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceEndIndication))
+	})
+}
+
+// OnConferenceHoldChangeIndication registers a handler for WS_CONF_HOLD_CHANGE_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceHoldChangeIndication(fn func(session *ZoomSession, message *ConferenceHoldChangeIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceHoldChangeIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceHoldChangeIndication))
+	})
+}
+
+// OnConferenceRoleChangeRequest registers a handler for WS_CONF_ROLE_CHANGE_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceRoleChangeRequest(fn func(session *ZoomSession, message *ConferenceRoleChangeRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceRoleChangeRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceRoleChangeRequest))
+	})
+}
+
+// OnConferenceRoleChangeResponse registers a handler for WS_CONF_ROLE_CHANGE_RES messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnConferenceRoleChangeResponse(fn func(session *ZoomSession, message *ConferenceRoleChangeResponse) error) func() {
+	return session.handlers.add(reflect.TypeOf(ConferenceRoleChangeResponse{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*ConferenceRoleChangeResponse))
+	})
+}
+
+// OnAudioAllowTalkRequest registers a handler for WS_AUDIO_ALLOW_TALK_REQ messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnAudioAllowTalkRequest(fn func(session *ZoomSession, message *AudioAllowTalkRequest) error) func() {
+	return session.handlers.add(reflect.TypeOf(AudioAllowTalkRequest{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*AudioAllowTalkRequest))
+	})
+}
+
+// OnAudioAllowTalkResponse registers a handler for WS_AUDIO_ALLOW_TALK_RES messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnAudioAllowTalkResponse(fn func(session *ZoomSession, message *AudioAllowTalkResponse) error) func() {
+	return session.handlers.add(reflect.TypeOf(AudioAllowTalkResponse{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*AudioAllowTalkResponse))
+	})
+}
+
+// OnAudioAllowTalkIndication registers a handler for WS_AUDIO_ALLOW_TALK_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnAudioAllowTalkIndication(fn func(session *ZoomSession, message *AudioAllowTalkIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(AudioAllowTalkIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*AudioAllowTalkIndication))
+	})
+}
+
+// OnWebinarViewOnlyTelephonyIndication registers a handler for WS_WEBINAR_VIEW_ONLY_TELEPHONY_INDICATION messages.  Call the returned function to remove it.
+func (session *ZoomSession) OnWebinarViewOnlyTelephonyIndication(fn func(session *ZoomSession, message *WebinarViewOnlyTelephonyIndication) error) func() {
+	return session.handlers.add(reflect.TypeOf(WebinarViewOnlyTelephonyIndication{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*WebinarViewOnlyTelephonyIndication))
+	})
+}
@@ -2,57 +2,66 @@ package zoom
 
 import (
 	"bytes"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/base64"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 )
 
-func httpGet(client *http.Client, url string, headers http.Header) (*http.Response, error) {
-	request, err := http.NewRequest("GET", url, nil)
+func (session *ZoomSession) httpGet(ctx context.Context, rawURL string, headers http.Header) (*http.Response, error) {
+	request, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
 	if err != nil {
-		return nil, err
+		return nil, session.redactURLError(err)
 	}
 	request.Header = headers
-	return client.Do(request)
+	response, err := session.httpClient.Do(request)
+	if err != nil {
+		return nil, session.redactURLError(err)
+	}
+	return response, nil
 }
 
-func (session *ZoomSession) generateSignature(meetingNumber string) string {
-	ts := time.Now().Unix()
-
-	header := []byte(`{"alg":"HS256","typ":"JWT"}`)
-	payload := []byte(fmt.Sprintf(`{"sdkKey":"%s","iat":%d,"exp":%d,"mn":%s,"role":0}`, session.ZoomJwtApiKey, ts, ts+1800, meetingNumber))
-	message := base64.URLEncoding.EncodeToString(header) + "." + base64.URLEncoding.EncodeToString(payload)
-
-	h := hmac.New(sha256.New, []byte(session.ZoomJwtApiSecret))
-	h.Write([]byte(message))
-	return message + "." + base64.URLEncoding.EncodeToString(h.Sum(nil))
+// GetMeetingInfoData fetches the meeting info.  The string is the cookies zoom set, formatted for a Cookie header.
+//
+// Deprecated: the cookies are kept in the session's cookie jar (see CookieJar) and sent automatically, so the string is only kept for compatibility.
+func (session *ZoomSession) GetMeetingInfoData() (*MeetingInfo, string, error) {
+	meetingInfo, err := session.getMeetingInfoData(context.Background())
+	if err != nil {
+		return nil, "", err
+	}
+	var cookieValues []string
+	for _, cookie := range session.Cookies() {
+		cookieValues = append(cookieValues, cookie.Name+"="+cookie.Value)
+	}
+	return meetingInfo, strings.Join(cookieValues, "; "), nil
 }
 
-func (session *ZoomSession) GetMeetingInfoData() (*MeetingInfo, string, error) {
+func (session *ZoomSession) getMeetingInfoData(ctx context.Context) (*MeetingInfo, error) {
 	var meetingInfo MeetingInfo
 
+	signature, err := session.signer.Sign(ctx, session.MeetingNumber, session.role())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to sign meeting info request: %w", err)
+	}
+
 	// generate info url
 	values := url.Values{}
 	values.Set("meetingNumber", session.MeetingNumber)
-	values.Set("userName", session.Username)
+	values.Set("userName", session.CurrentUsername())
 	values.Set("passWord", session.MeetingPassword)
-	values.Set("signature", session.generateSignature(session.MeetingNumber))
+	values.Set("signature", signature)
 	// values.Set("apiKey", ZOOM_JWT_API_KEY)
 	values.Set("apiKey", session.ZoomJwtApiKey)
-	values.Set("lang", "en-US")
-	values.Set("userEmail", "")
-	values.Set("cv", "2.12.0")
+	values.Set("lang", session.language)
+	values.Set("userEmail", session.email)
+	values.Set("cv", session.sdkVersion)
 	values.Set("proxy", "1")
 	values.Set("sdkOrigin", "aHR0cDovL2xvY2FsaG9zdDo5OTk5")
-	values.Set("tk", "")
-	values.Set("ztk", "")
+	values.Set("tk", session.registrantToken)
+	values.Set("ztk", session.ztk)
 	values.Set("sdkUrl", "aHR0cDovL2xvY2FsaG9zdDo5OTk5L21lZXRpbmcuaHRtbA")
 	values.Set("captcha", "")
 	values.Set("captchaName", "")
@@ -60,15 +69,17 @@ func (session *ZoomSession) GetMeetingInfoData() (*MeetingInfo, string, error) {
 	values.Set("callback", "axiosJsonpCallback1")
 	values.Set("signatureType", "sdk")
 
-	response, err := httpGet(session.httpClient, fmt.Sprintf("https://zoom.us/api/v1/wc/info?%s", values.Encode()), httpHeaders())
+	infoURL := session.Endpoints.Info + "?" + values.Encode()
+	session.logger.Debug("Fetching meeting info", "url", session.redactURL(infoURL))
+	response, err := session.httpGet(ctx, infoURL, session.httpHeaders())
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 	defer response.Body.Close()
 
 	data, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return nil, "", err
+		return nil, err
 	}
 
 	getStringInBetweenTwoString := func(str []byte, startS []byte, endS []byte) []byte {
@@ -78,7 +89,8 @@ func (session *ZoomSession) GetMeetingInfoData() (*MeetingInfo, string, error) {
 		}
 		newS := str[s+len(startS):]
 
-		e := bytes.Index(newS, endS)
+		// last index since error messages can have brackets in them
+		e := bytes.LastIndex(newS, endS)
 		if e == -1 {
 			return nil
 		}
@@ -86,27 +98,30 @@ func (session *ZoomSession) GetMeetingInfoData() (*MeetingInfo, string, error) {
 	}
 
 	if err = json.Unmarshal(getStringInBetweenTwoString(data, []byte("osJsonpCallback1("), []byte(")")), &meetingInfo); err != nil {
-		return nil, "", err
+		return nil, err
+	}
+	if !meetingInfo.Status || meetingInfo.ErrorCode != 0 {
+		infoErr := newMeetingInfoError(&meetingInfo)
+		infoErr.Hint = session.identityHint(infoErr.Err)
+		return nil, infoErr
 	}
 
-	var cookieString string
-	for _, cookieValue := range response.Cookies() {
-		// skip "cred" and empty cookies
-		if cookieValue.Name == "cred" || cookieValue.Value != "" {
-			cookieString += fmt.Sprintf("%s=%s; ", cookieValue.Name, cookieValue.Value)
-		}
+	session.mu.Lock()
+	session.isWebinar = meetingInfo.Result.IsWebinar == 1
+	session.mu.Unlock()
+	// the host has a zak, everyone else joins as an attendee and the web sdk won't let attendees join without userEmail
+	if meetingInfo.Result.IsWebinar == 1 && session.email == "" && session.zak == "" {
+		return nil, fmt.Errorf("%w: webinar attendees have to give one, pass it with WithEmail", ErrEmailRequired)
 	}
-	// remove trailing ";"" if it exists
-	cookieString = strings.TrimSuffix(cookieString, "; ")
 
-	return &meetingInfo, cookieString, nil
+	return &meetingInfo, nil
 }
 
-func (session *ZoomSession) getRwgPingData(meetingInfo *MeetingInfo, pingRwcServer *RwgInfo) (*RwgInfo, error) {
-	headers := httpHeaders()
+func (session *ZoomSession) getRwgPingData(ctx context.Context, meetingInfo *MeetingInfo, pingRwcServer *RwgInfo) (*RwgInfo, error) {
+	headers := session.httpHeaders()
 	headers.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	response, err := httpGet(session.httpClient, fmt.Sprintf("https://%s/wc/ping/%s?ts=%d&auth=%s&rwcToken=%s&dmz=1", pingRwcServer.Rwg, meetingInfo.Result.MeetingNumber, meetingInfo.Result.Ts, meetingInfo.Result.Auth, pingRwcServer.RwcAuth), headers)
+	response, err := session.httpGet(ctx, fmt.Sprintf("%s://%s/wc/ping/%s?ts=%d&auth=%s&rwcToken=%s&dmz=1", session.Endpoints.PingScheme, pingRwcServer.Rwg, meetingInfo.Result.MeetingNumber, meetingInfo.Result.Ts, meetingInfo.Result.Auth, pingRwcServer.RwcAuth), headers)
 	if err != nil {
 		return nil, err
 	}
@@ -118,3 +133,16 @@ func (session *ZoomSession) getRwgPingData(meetingInfo *MeetingInfo, pingRwcServ
 	}
 	return &rwgPingInfo, nil
 }
+
+// identityHint says which option is missing when zoom wants to know who we are
+func (session *ZoomSession) identityHint(err error) string {
+	switch {
+	case err == ErrRegistrationRequired && session.registrantToken == "":
+		return "Register for the meeting and pass the email and registrant token (the tk parameter of the join link) with WithEmail and WithRegistrantToken"
+	case err == ErrLoginRequired && session.ztk == "":
+		return "Only signed in users can join, pass a ZTK for the account with WithZTK"
+	case err == ErrEmailRequired && session.email == "":
+		return "Pass an email address with WithEmail"
+	}
+	return ""
+}
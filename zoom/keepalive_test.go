@@ -0,0 +1,61 @@
+package zoom_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+func TestStaleConnection(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	server.KeepaliveInterval = 0
+	server.IgnorePings = true
+	session := newTestSession(t, server, zoom.WithKeepalive(50*time.Millisecond, 200*time.Millisecond), zoom.WithReconnectPolicy(nil))
+
+	runErr := startSession(t, session, nil)
+	if err := waitForRun(t, runErr); err != zoom.ErrConnectionStale {
+		t.Fatalf("Run returned %v, wanted ErrConnectionStale", err)
+	}
+}
+
+func TestPongsKeepConnectionAlive(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	server.KeepaliveInterval = 0
+	session := newTestSession(t, server, zoom.WithKeepalive(50*time.Millisecond, 200*time.Millisecond), zoom.WithReconnectPolicy(nil))
+
+	runErr := startSession(t, session, nil)
+	select {
+	case err := <-runErr:
+		t.Fatalf("Run returned %v while the server was answering pings", err)
+	case <-time.After(time.Second):
+	}
+	leave(t, session, runErr)
+}
+
+func TestKeepaliveValidation(t *testing.T) {
+	for _, test := range []struct {
+		interval     time.Duration
+		staleTimeout time.Duration
+		ok           bool
+	}{
+		{time.Minute, 3 * time.Minute, true},
+		{time.Minute, 0, true},
+		{0, 3 * time.Minute, false},
+		{-time.Minute, 3 * time.Minute, false},
+		{time.Minute, time.Minute, false},
+		{time.Minute, time.Second, false},
+		{time.Minute, -time.Minute, false},
+	} {
+		_, err := zoom.New(zoomtest.MeetingNumber, zoom.WithCredentials("testKey", "testSecret"), zoom.WithKeepalive(test.interval, test.staleTimeout))
+		if test.ok && err != nil {
+			t.Errorf("WithKeepalive(%s, %s) was refused: %v", test.interval, test.staleTimeout, err)
+		}
+		if !test.ok && err == nil {
+			t.Errorf("WithKeepalive(%s, %s) was accepted", test.interval, test.staleTimeout)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package zoom
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// Logger is what the session logs to.  *slog.Logger implements it, as does NewStdLogger for the standard log package.
+// args are alternating keys and values, the same as slog
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// Level is a logging level for NewStdLogger.  the values are the same as slog's
+type Level int
+
+const (
+	LevelDebug Level = -4
+	LevelInfo  Level = 0
+	LevelWarn  Level = 4
+	LevelError Level = 8
+)
+
+func (level Level) String() string {
+	switch level {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LEVEL(%d)", int(level))
+}
+
+type stdLogger struct {
+	logger *log.Logger
+	level  Level
+}
+
+// NewStdLogger adapts a *log.Logger to Logger, dropping anything below level.  lines look like `INFO Reconnected attempts=1`
+func NewStdLogger(logger *log.Logger, level Level) Logger {
+	return &stdLogger{logger: logger, level: level}
+}
+
+func (l *stdLogger) log(level Level, msg string, args []interface{}) {
+	if level < l.level {
+		return
+	}
+	var line strings.Builder
+	line.WriteString(level.String())
+	line.WriteString(" ")
+	line.WriteString(msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 < len(args) {
+			fmt.Fprintf(&line, " %v=%v", args[i], args[i+1])
+		} else {
+			fmt.Fprintf(&line, " !BADKEY=%v", args[i])
+		}
+	}
+	l.logger.Print(line.String())
+}
+
+func (l *stdLogger) Debug(msg string, args ...interface{}) { l.log(LevelDebug, msg, args) }
+func (l *stdLogger) Info(msg string, args ...interface{})  { l.log(LevelInfo, msg, args) }
+func (l *stdLogger) Warn(msg string, args ...interface{})  { l.log(LevelWarn, msg, args) }
+func (l *stdLogger) Error(msg string, args ...interface{}) { l.log(LevelError, msg, args) }
+
+const redacted = "[redacted]"
+
+// messages whose bodies have chat text or tokens in them
+var redactedMessages = map[int]bool{
+	WS_CONF_CHAT_REQ:                true,
+	WS_CONF_CHAT_INDICATION:         true,
+	WS_CONF_BO_BROADCAST_REQ:        true,
+	WS_CONF_BO_COMMAND_INDICATION:   true,
+	WS_CONF_BO_JOIN_RES:             true,
+	WS_CONF_OPTION_INDICATION:       true, // opt
+	WS_CONF_BO_ATTRIBUTE_INDICATION: true, // each room's MeetingToken
+	WS_CONF_BO_START_REQ:            true,
+	WS_CONF_ATTRIBUTE_INDICATION:    true, // encryptKey
+}
+
+// query parameters that are credentials or tokens
+var redactedParameters = []string{"signature", "apiKey", "passWord", "mpwd", "auth", "sign", "trackAuth", "rwcAuth", "rwcToken", "zak", "tk", "ztk", "opt", "userEmail", "email"}
+
+// logMessage traces a websocket message, direction is "send" or "recv"
+func (session *ZoomSession) logMessage(direction string, message *GenericZoomMessage) {
+	body := string(message.Body)
+	if session.redact && redactedMessages[message.Evt] {
+		body = redacted
+	}
+	session.logger.Debug("Websocket message", "direction", direction, "evt", MessageNumberToName[message.Evt], "evtNumber", message.Evt, "seq", message.Seq, "body", body)
+}
+
+// redactURLError hides credentials in the url net/http puts in its errors, which end up in logs and in what Connect returns
+func (session *ZoomSession) redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		redactedErr := *urlErr
+		redactedErr.URL = session.redactURL(urlErr.URL)
+		return &redactedErr
+	}
+	return err
+}
+
+// redactURL hides credentials in the query string of rawURL if redaction is on
+func (session *ZoomSession) redactURL(rawURL string) string {
+	if !session.redact {
+		return rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+	values := parsed.Query()
+	for _, parameter := range redactedParameters {
+		if values.Get(parameter) != "" {
+			values.Set(parameter, redacted)
+		}
+	}
+	parsed.RawQuery = values.Encode()
+	return parsed.String()
+}
@@ -0,0 +1,92 @@
+package zoom_test
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+// lockedBuffer is written to by the session's goroutines and read by the test
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestConnectErrorIsRedacted(t *testing.T) {
+	// a server that is gone by the time we connect
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	var logs lockedBuffer
+	session, err := zoom.New(zoomtest.MeetingNumber,
+		zoom.WithCredentials("testKey", "testSecret"),
+		zoom.WithPassword("hunter2"),
+		zoom.WithLogger(zoom.NewStdLogger(log.New(&logs, "", 0), zoom.LevelDebug)),
+		zoom.WithEndpoints(zoom.Endpoints{Info: server.URL + "/api/v1/wc/info", PingScheme: "http", WebsocketScheme: "ws"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	err = session.Connect(ctx)
+	if err == nil {
+		t.Fatal("Connect to a closed server succeeded")
+	}
+	for _, leaked := range []string{err.Error(), logs.String()} {
+		if strings.Contains(leaked, "hunter2") || strings.Contains(leaked, "signature=ey") {
+			t.Errorf("Credentials leaked: %s", leaked)
+		}
+	}
+}
+
+func TestMessageBodiesAreRedacted(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	var logs lockedBuffer
+	session := newTestSession(t, server, zoom.WithLogger(zoom.NewStdLogger(log.New(&logs, "", 0), zoom.LevelDebug)))
+	runErr := startSession(t, session, nil)
+
+	server.Send(zoom.WS_CONF_OPTION_INDICATION, zoom.ConferenceOptionIndication{Opt: "secretOpt"})
+	server.Send(zoom.WS_CONF_CHAT_INDICATION, zoom.ConferenceChatIndication{Text: []byte("last")})
+	waitForLog(t, &logs, "WS_CONF_CHAT_INDICATION")
+	leave(t, session, runErr)
+
+	for _, secret := range []string{"secretOpt", "bCanUnmuteVideo"} {
+		if strings.Contains(logs.String(), secret) {
+			t.Errorf("%s was logged", secret)
+		}
+	}
+}
+
+func waitForLog(t *testing.T, logs *lockedBuffer, substring string) {
+	t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for !strings.Contains(logs.String(), substring) {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s was never logged", substring)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
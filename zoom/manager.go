@@ -0,0 +1,225 @@
+package zoom
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// returned by Manager.Start when the concurrency limit has been reached
+var ErrTooManySessions = errors.New("Too many sessions running")
+
+// returned by Manager.Start when there is already a session for the meeting
+var ErrSessionExists = errors.New("Session already running for meeting")
+
+// returned by Manager.Stop when there is no session for the meeting
+var ErrNoSession = errors.New("No session running for meeting")
+
+// ManagerEvent is a message from one of a Manager's sessions, tagged with the meeting it came from
+type ManagerEvent struct {
+	MeetingNumber string
+	Session       *ZoomSession
+	Message       Message
+}
+
+// SessionStopped is sent (through the manager's event function only) when a managed session's Run returns.  Err is what Run returned.
+type SessionStopped struct {
+	Err error
+}
+
+// SessionInfo describes a live session, see Manager.Sessions
+type SessionInfo struct {
+	MeetingNumber string
+	State         State
+	Started       time.Time
+}
+
+// Manager runs many sessions in one process.  Sessions share one http transport (unless they are given their own proxy, client or tls config) and all of their messages go to a single event function.
+type Manager struct {
+	mu sync.Mutex
+
+	maxSessions int
+	options     []Option
+	httpClient  *http.Client
+	onEvent     func(event *ManagerEvent) error
+	sessions    map[string]*managedSession
+}
+
+type managedSession struct {
+	session *ZoomSession
+	started time.Time
+	cancel  context.CancelFunc
+}
+
+// NewManager creates a manager that runs at most maxSessions sessions at once (0 means no limit).  options are applied to every session before the ones passed to Start.
+// onEvent (which may be nil) is called for every message received by any session, like the onMessage function passed to Run.  Each session calls it from its own handler goroutine (see Run), so it is called for several sessions at once.
+func NewManager(maxSessions int, onEvent func(event *ManagerEvent) error, options ...Option) *Manager {
+	return &Manager{
+		maxSessions: maxSessions,
+		options:     options,
+		httpClient: &http.Client{
+			Timeout: 35 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig:     defaultTLSConfig,
+				MaxIdleConnsPerHost: 16, // most of our requests go to the same few zoom hosts
+			},
+		},
+		onEvent:  onEvent,
+		sessions: make(map[string]*managedSession),
+	}
+}
+
+// use the manager's client unless the session has been given its own client, proxy or tls config.  New makes a client with those for the session as usual, so they apply to the http requests as well as the websocket
+func withSharedHTTPClient(client *http.Client) Option {
+	return func(session *ZoomSession) error {
+		if session.httpClient == nil && session.ProxyURL == nil && session.tlsConfig == defaultTLSConfig {
+			// copy so the session keeps its own timeout, the transport (and its connection pool) is still shared
+			client := *client
+			client.Timeout = session.httpTimeout
+			session.httpClient = &client
+		}
+		return nil
+	}
+}
+
+// Start creates a session for the meeting, connects it and runs it in the background until Stop is called or it stops by itself.  ctx is only used for connecting.
+func (manager *Manager) Start(ctx context.Context, meetingNumber string, options ...Option) (*ZoomSession, error) {
+	options = append(append(append([]Option{}, manager.options...), options...), withSharedHTTPClient(manager.httpClient))
+	session, err := New(meetingNumber, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	// reserve the slot before connecting so we don't go over the limit while connecting
+	managed := &managedSession{
+		session: session,
+		started: time.Now(),
+	}
+	manager.mu.Lock()
+	if _, ok := manager.sessions[session.MeetingNumber]; ok {
+		manager.mu.Unlock()
+		return nil, ErrSessionExists
+	}
+	if manager.maxSessions > 0 && len(manager.sessions) >= manager.maxSessions {
+		manager.mu.Unlock()
+		return nil, ErrTooManySessions
+	}
+	manager.sessions[session.MeetingNumber] = managed
+	manager.mu.Unlock()
+
+	if err := session.Connect(ctx); err != nil {
+		manager.remove(session.MeetingNumber, managed)
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	manager.mu.Lock()
+	managed.cancel = cancel
+	manager.mu.Unlock()
+	go func() {
+		defer cancel()
+		err := session.Run(runCtx, manager.onMessage(session.MeetingNumber))
+		manager.remove(session.MeetingNumber, managed)
+		manager.onMessage(session.MeetingNumber)(session, &SessionStopped{Err: err})
+	}()
+	return session, nil
+}
+
+func (manager *Manager) onMessage(meetingNumber string) onMessage {
+	return func(session *ZoomSession, message Message) error {
+		if manager.onEvent == nil {
+			return nil
+		}
+		return manager.onEvent(&ManagerEvent{
+			MeetingNumber: meetingNumber,
+			Session:       session,
+			Message:       message,
+		})
+	}
+}
+
+func (manager *Manager) remove(meetingNumber string, managed *managedSession) {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	if manager.sessions[meetingNumber] == managed {
+		delete(manager.sessions, meetingNumber)
+	}
+}
+
+// Stop leaves the meeting and waits for the connection to close.  If ctx is cancelled first the connection is closed without waiting for zoom.
+// Like Leave it doesn't wait for the session's handlers, so it can be called from onEvent.  SessionStopped is sent once they have finished, which can be after Stop returns.
+func (manager *Manager) Stop(ctx context.Context, meetingNumber string) error {
+	manager.mu.Lock()
+	managed, ok := manager.sessions[meetingNumber]
+	var cancel context.CancelFunc
+	if ok {
+		cancel = managed.cancel
+	}
+	manager.mu.Unlock()
+	if cancel == nil {
+		// still connecting counts as not running
+		return ErrNoSession
+	}
+
+	err := managed.session.Leave(ctx)
+	if err != nil {
+		cancel()
+	}
+	// the session's Run does this too when it returns, but that can be after the handlers it is still running
+	manager.remove(meetingNumber, managed)
+	return err
+}
+
+// StopAll stops every session at the same time and returns the first error
+func (manager *Manager) StopAll(ctx context.Context) error {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	for _, info := range manager.Sessions() {
+		wg.Add(1)
+		go func(meetingNumber string) {
+			defer wg.Done()
+			if err := manager.Stop(ctx, meetingNumber); err != nil && err != ErrNoSession {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(info.MeetingNumber)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// Session returns the live session for the meeting, or nil
+func (manager *Manager) Session(meetingNumber string) *ZoomSession {
+	manager.mu.Lock()
+	defer manager.mu.Unlock()
+	if managed, ok := manager.sessions[meetingNumber]; ok {
+		return managed.session
+	}
+	return nil
+}
+
+// Sessions lists the live sessions (including ones still connecting) sorted by meeting number
+func (manager *Manager) Sessions() []SessionInfo {
+	manager.mu.Lock()
+	infos := make([]SessionInfo, 0, len(manager.sessions))
+	for meetingNumber, managed := range manager.sessions {
+		infos = append(infos, SessionInfo{
+			MeetingNumber: meetingNumber,
+			State:         managed.session.State(),
+			Started:       managed.started,
+		})
+	}
+	manager.mu.Unlock()
+
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].MeetingNumber < infos[j].MeetingNumber
+	})
+	return infos
+}
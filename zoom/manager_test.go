@@ -0,0 +1,62 @@
+package zoom_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+func TestManagerStopFromEvent(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+
+	stopped := make(chan error, 1)
+	sessionStopped := make(chan *zoom.SessionStopped, 1)
+	var manager *zoom.Manager
+	manager = zoom.NewManager(0, func(event *zoom.ManagerEvent) error {
+		switch message := event.Message.(type) {
+		case *zoom.ConferenceChatIndication:
+			if string(message.Text) == "++stop" {
+				ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+				defer cancel()
+				stopped <- manager.Stop(ctx, event.MeetingNumber)
+			}
+		case *zoom.SessionStopped:
+			sessionStopped <- message
+		}
+		return nil
+	}, testOptions(server)...)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	session, err := manager.Start(ctx, zoomtest.MeetingNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitForState(t, session, zoom.StateInMeeting)
+
+	server.Send(zoom.WS_CONF_CHAT_INDICATION, zoom.ConferenceChatIndication{Text: []byte("++stop")})
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatalf("Stop from onEvent returned %v", err)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("Stop from onEvent did not return")
+	}
+	select {
+	case message := <-sessionStopped:
+		if message.Err != nil {
+			t.Errorf("SessionStopped has error %v", message.Err)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("No SessionStopped event")
+	}
+	waitFor(t, server, zoom.WS_CONF_LEAVE_REQ)
+	if sessions := manager.Sessions(); len(sessions) != 0 {
+		t.Errorf("Manager still has %d sessions", len(sessions))
+	}
+}
@@ -2,8 +2,6 @@ package zoom
 
 import (
 	"encoding/json"
-	"fmt"
-	"log"
 	"reflect"
 
 	"github.com/gorilla/websocket"
@@ -60,22 +58,36 @@ var msgTypes = map[int]reflect.Type{
 	WS_CONF_BO_JOIN_REQ: reflect.TypeOf(ConferenceBreakoutRoomJoinRequest{}),
 	WS_CONF_BO_JOIN_RES: reflect.TypeOf(ConferenceBreakoutRoomJoinResponse{}),
 	// sender implemented, untested
+	WS_CONF_BO_LEAVE_REQ: reflect.TypeOf(ConferenceBreakoutRoomLeaveRequest{}),
+	// sender implemented, untested
 	WS_CONF_END_REQ: reflect.TypeOf(ConferenceEndRequest{}),
+	// sender implemented, untested
+	WS_CONF_LEAVE_REQ: reflect.TypeOf(ConferenceLeaveRequest{}),
+	WS_CONF_LEAVE_RES: reflect.TypeOf(ConferenceLeaveResponse{}),
 	// sender implemented, doesn't work???
 	WS_CONF_BO_TOKEN_BATCH_REQ:     reflect.TypeOf(ConferenceBreakoutRoomTokenBatchRequest{}),
 	WS_CONF_BO_TOKEN_RES:           reflect.TypeOf(ConferenceBreakoutRoomTokenResponse{}),
 	WS_CONF_HOST_CHANGE_INDICATION: reflect.TypeOf(ConferenceHostChangeIndication{}),
 	WS_CONF_END_INDICATION:         reflect.TypeOf(ConferenceEndIndication{}),
+	WS_CONF_HOLD_CHANGE_INDICATION: reflect.TypeOf(ConferenceHoldChangeIndication{}),
+	// sender implemented, untested
+	WS_CONF_ROLE_CHANGE_REQ: reflect.TypeOf(ConferenceRoleChangeRequest{}),
+	WS_CONF_ROLE_CHANGE_RES: reflect.TypeOf(ConferenceRoleChangeResponse{}),
+	// sender implemented, untested
+	WS_AUDIO_ALLOW_TALK_REQ:                   reflect.TypeOf(AudioAllowTalkRequest{}),
+	WS_AUDIO_ALLOW_TALK_RES:                   reflect.TypeOf(AudioAllowTalkResponse{}),
+	WS_AUDIO_ALLOW_TALK_INDICATION:            reflect.TypeOf(AudioAllowTalkIndication{}),
+	WS_WEBINAR_VIEW_ONLY_TELEPHONY_INDICATION: reflect.TypeOf(WebinarViewOnlyTelephonyIndication{}),
 }
 
 func GetMessageBody(message *GenericZoomMessage) (interface{}, error) {
 	typ := msgTypes[message.Evt]
 	if typ == nil {
-		return nil, fmt.Errorf("Failed to get message body: missing type definition in zoom/message.go")
+		return nil, &DecodeError{Evt: message.Evt, Seq: message.Seq, Raw: message.Body, Err: ErrUnknownMessage}
 	}
 	p := reflect.New(typ).Interface()
 	if err := json.Unmarshal(message.Body, p); err != nil {
-		return nil, fmt.Errorf("Failed to parse body JSON: %+v", err)
+		return nil, &DecodeError{Evt: message.Evt, Seq: message.Seq, Raw: message.Body, Err: err}
 	}
 	return p, nil
 }
@@ -83,7 +95,42 @@ func GetMessageBody(message *GenericZoomMessage) (interface{}, error) {
 func (session *ZoomSession) SendMessage(connection *websocket.Conn, eventNumber int, body interface{}) error {
 	session.mu.Lock() // gorilla/websocket only allows for 1 sender at a time + the send sequence number shouldn't be written to simultaneously
 	defer session.mu.Unlock()
+	return session.sendLocked(connection, eventNumber, body)
+}
+
+// send is SendMessage on the current connection.  the connection is read under the lock since Run replaces it when reconnecting or changing rooms
+func (session *ZoomSession) send(eventNumber int, body interface{}) error {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.sendLocked(session.websocketConnection, eventNumber, body)
+}
+
+// currentConnection returns the connection made by the last Connect (or reconnect)
+func (session *ZoomSession) currentConnection() *websocket.Conn {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.websocketConnection
+}
+
+// CurrentJoinInfo returns JoinInfo, which the reader replaces every time we join.  use it rather than the field while Run is running
+func (session *ZoomSession) CurrentJoinInfo() JoinConferenceResponse {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.JoinInfo
+}
 
+// CurrentUsername returns Username, which RenameMe changes.  use it rather than the field while Run is running
+func (session *ZoomSession) CurrentUsername() string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.Username
+}
+
+// must be called with mu held
+func (session *ZoomSession) sendLocked(connection *websocket.Conn, eventNumber int, body interface{}) error {
+	if connection == nil {
+		return ErrNotConnected
+	}
 	session.sendSequenceNumber++
 
 	message := GenericZoomMessage{
@@ -98,7 +145,7 @@ func (session *ZoomSession) SendMessage(connection *websocket.Conn, eventNumber
 		}
 		message.Body = bodyBytes
 	}
-	log.Printf("Sending message (Evt: %s; Seq: %d): %s", MessageNumberToName[message.Evt], message.Seq, string(message.Body))
+	session.logMessage("send", &message)
 
 	return connection.WriteJSON(message)
 }
@@ -54,21 +54,7 @@ type ConferenceAttributeIndication map[string]interface{}
 
 // there are many types of roster indication messages so we just omitempty everything so that we aren't sending a bunch of blank strings etc
 type ConferenceRosterIndication struct {
-	Add []struct {
-		Avatar             string               `json:"avatar,omitempty"`
-		BCCEditor          bool                 `json:"bCCEditor,omitempty"`
-		BCanPinMultiVideo  bool                 `json:"bCanPinMultiVideo,omitempty"`
-		BCapsPinMultiVideo bool                 `json:"bCapsPinMultiVideo,omitempty"`
-		BGuest             bool                 `json:"bGuest,omitempty"`
-		BHold              bool                 `json:"bHold,omitempty"`
-		BRaiseHand         bool                 `json:"bRaiseHand,omitempty"`
-		Dn2                BytesBase64NoPadding `json:"dn2,omitempty"`
-		ID                 int                  `json:"id,omitempty"`
-		Os                 int                  `json:"os,omitempty"`
-		Role               int                  `json:"role,omitempty"`
-		Type               int                  `json:"type,omitempty"`
-		ZoomID             string               `json:"zoomID,omitempty"`
-	} `json:"add"`
+	Add    []RosterAddItem `json:"add"`
 	Update []struct {
 		// all these fields are optional
 		Caps     int                  `json:"caps,omitempty"`
@@ -76,7 +62,7 @@ type ConferenceRosterIndication struct {
 		ID       int                  `json:"id,omitempty"`
 		Muted    bool                 `json:"muted,omitempty"`
 		BVideoOn bool                 `json:"bVideoOn,omitempty"`
-		Audio    string               `json:"audio,omitempty`
+		Audio    string               `json:"audio,omitempty"`
 		BCoHost  bool                 `json:"bCoHost,omitempty"`
 		Role     int                  `json:"role,omitempty"`
 	} `json:"update"`
@@ -86,6 +72,22 @@ type ConferenceRosterIndication struct {
 	} `json:"remove"`
 }
 
+type RosterAddItem struct {
+	Avatar             string               `json:"avatar,omitempty"`
+	BCCEditor          bool                 `json:"bCCEditor,omitempty"`
+	BCanPinMultiVideo  bool                 `json:"bCanPinMultiVideo,omitempty"`
+	BCapsPinMultiVideo bool                 `json:"bCapsPinMultiVideo,omitempty"`
+	BGuest             bool                 `json:"bGuest,omitempty"`
+	BHold              bool                 `json:"bHold,omitempty"`
+	BRaiseHand         bool                 `json:"bRaiseHand,omitempty"`
+	Dn2                BytesBase64NoPadding `json:"dn2,omitempty"`
+	ID                 int                  `json:"id,omitempty"`
+	Os                 int                  `json:"os,omitempty"`
+	Role               int                  `json:"role,omitempty"`
+	Type               int                  `json:"type,omitempty"`
+	ZoomID             string               `json:"zoomID,omitempty"`
+}
+
 type ConferenceEndIndication struct {
 	Reason    int `json:"reason"`
 	SubReason int `json:"subReason"`
@@ -134,6 +136,10 @@ type ConferenceLockShareRequest struct {
 
 type ConferenceEndRequest struct{}
 
+type ConferenceLeaveRequest struct{}
+
+type ConferenceLeaveResponse struct{}
+
 type ConferenceLocalRecordIndication struct{}
 
 type ConferenceOptionIndication struct {
@@ -200,6 +206,8 @@ type ConferenceBreakoutRoomJoinResponse struct {
 	ConfID  string `json:"confID"`
 }
 
+type ConferenceBreakoutRoomLeaveRequest struct{}
+
 type ConferenceBreakoutRoomCommandIndication struct {
 	// although this could be represented as bytesbase64nopadding data there is no point in doing so because we are not going to be anything other than copying it verbatim
 	Botoken     string               `json:"botoken,omitempty"`
@@ -247,6 +255,37 @@ type BreakoutRoomItem struct {
 	ParticipantList []string `json:"ParticipantList"`
 }
 
+// webinars.  field names follow the rest of the protocol but none of these have been seen from a real webinar yet
+type ConferenceRoleChangeRequest struct {
+	ID   int `json:"id"`
+	Role int `json:"role"` // WebinarRolePanelist or WebinarRoleAttendee
+}
+
+type ConferenceRoleChangeResponse struct {
+	ID     int `json:"id"`
+	Role   int `json:"role"`
+	Result int `json:"result"`
+}
+
+type AudioAllowTalkRequest struct {
+	ID         int  `json:"id"`
+	BAllowTalk bool `json:"bAllowTalk"`
+}
+
+type AudioAllowTalkResponse struct {
+	ID         int  `json:"id"`
+	BAllowTalk bool `json:"bAllowTalk"`
+	Result     int  `json:"result"`
+}
+
+// sent to an attendee when the host lets them talk or stops them
+type AudioAllowTalkIndication struct {
+	BAllowTalk bool `json:"bAllowTalk"`
+}
+
+// sent to webinar attendees (who are view only) with the numbers for listening by phone
+type WebinarViewOnlyTelephonyIndication map[string]interface{}
+
 type ConferenceHoldChangeIndication struct {
 	BHold bool `json:"bHold"`
 }
@@ -0,0 +1,303 @@
+package zoom
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Option configures a ZoomSession, see New
+type Option func(session *ZoomSession) error
+
+// WithCredentials sets the Meeting SDK key and secret used to sign the join request
+func WithCredentials(key string, secret string) Option {
+	return func(session *ZoomSession) error {
+		session.ZoomJwtApiKey = key
+		session.ZoomJwtApiSecret = secret
+		return nil
+	}
+}
+
+// WithPassword sets the meeting password.  leave it out for meetings without one
+func WithPassword(password string) Option {
+	return func(session *ZoomSession) error {
+		session.MeetingPassword = password
+		return nil
+	}
+}
+
+// WithDisplayName sets the name shown to other participants (default "Bot")
+func WithDisplayName(name string) Option {
+	return func(session *ZoomSession) error {
+		session.Username = name
+		return nil
+	}
+}
+
+// WithHardwareID sets the device id (a UUID) we report to zoom.  it should stay the same between runs or it will appear to zoom that you have many many many devices
+func WithHardwareID(hardwareID string) Option {
+	return func(session *ZoomSession) error {
+		uuidParsed, err := uuid.Parse(hardwareID)
+		if err != nil {
+			return err
+		}
+		session.HardwareID = uuidParsed
+		return nil
+	}
+}
+
+// WithProxy sends the meeting info requests and the websocket through a proxy
+func WithProxy(proxyURL string) Option {
+	return func(session *ZoomSession) error {
+		proxyUrlParsed, err := url.Parse(proxyURL)
+		if err != nil {
+			return err
+		}
+		session.ProxyURL = proxyUrlParsed
+		return nil
+	}
+}
+
+// WithHTTPClient uses client for the meeting info and RWG ping requests.  the proxy, TLS config and HTTP timeout options do not apply to a client passed in this way, but still apply to the websocket
+func WithHTTPClient(client *http.Client) Option {
+	return func(session *ZoomSession) error {
+		session.httpClient = client
+		return nil
+	}
+}
+
+// WithTLSConfig sets the TLS configuration for the meeting info requests, the RWG ping and the websocket.  certificates are verified by default
+func WithTLSConfig(config *tls.Config) Option {
+	return func(session *ZoomSession) error {
+		session.tlsConfig = config
+		return nil
+	}
+}
+
+// WithDebugProxy sends all traffic through an intercepting proxy like Charles or mitmproxy and trusts the CA certificates (PEM) in caBundlePath on top of the system ones, so the proxy can decrypt the traffic without turning off certificate verification
+func WithDebugProxy(proxyURL string, caBundlePath string) Option {
+	return func(session *ZoomSession) error {
+		if err := WithProxy(proxyURL)(session); err != nil {
+			return err
+		}
+
+		caBundle, err := ioutil.ReadFile(caBundlePath)
+		if err != nil {
+			return err
+		}
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(caBundle) {
+			return errors.New("No certificates found in debug proxy CA bundle")
+		}
+
+		config := session.tlsConfig.Clone()
+		if config == nil {
+			config = &tls.Config{}
+		}
+		config.RootCAs = rootCAs
+		session.tlsConfig = config
+		return nil
+	}
+}
+
+// WithUserAgent changes the browser we pretend to be (default DefaultUserAgent)
+func WithUserAgent(userAgent UserAgent) Option {
+	return func(session *ZoomSession) error {
+		session.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithSDKVersion changes the web SDK version we report to zoom (the "cv" and "jscv" parameters, default DefaultSDKVersion)
+func WithSDKVersion(version string) Option {
+	return func(session *ZoomSession) error {
+		session.sdkVersion = version
+		return nil
+	}
+}
+
+// WithLanguage sets the language zoom uses for the meeting, for example "en-US" (the default) or "de-DE"
+func WithLanguage(language string) Option {
+	return func(session *ZoomSession) error {
+		session.language = language
+		return nil
+	}
+}
+
+// WithHTTPTimeout limits how long the meeting info and RWG ping requests can take (default 35 seconds)
+func WithHTTPTimeout(timeout time.Duration) Option {
+	return func(session *ZoomSession) error {
+		session.httpTimeout = timeout
+		return nil
+	}
+}
+
+// WithHandshakeTimeout limits how long opening the websocket can take (default 35 seconds)
+func WithHandshakeTimeout(timeout time.Duration) Option {
+	return func(session *ZoomSession) error {
+		session.handshakeTimeout = timeout
+		return nil
+	}
+}
+
+// WithResponseTimeout limits how long SendAndWait waits for a response when its context has no deadline (default 15 seconds)
+func WithResponseTimeout(timeout time.Duration) Option {
+	return func(session *ZoomSession) error {
+		session.responseTimeout = timeout
+		return nil
+	}
+}
+
+// WithKeepalive sets how often we send keepalives and websocket pings (default 60 seconds) and how long the server can go without sending anything before the connection is considered dead (default 3 minutes).
+// a dead connection is closed and reported as ErrConnectionStale, which triggers reconnection.  a staleTimeout of 0 disables the check, otherwise it has to be longer than interval since our pings are what make a quiet but healthy server answer
+func WithKeepalive(interval time.Duration, staleTimeout time.Duration) Option {
+	return func(session *ZoomSession) error {
+		if interval <= 0 {
+			return errors.New("Keepalive interval must be positive")
+		}
+		if staleTimeout < 0 || (staleTimeout > 0 && staleTimeout <= interval) {
+			return errors.New("Stale timeout must be 0 or longer than the keepalive interval")
+		}
+		session.keepalive = interval
+		session.staleTimeout = staleTimeout
+		return nil
+	}
+}
+
+// WithWaitingRoomTimeout makes Run give up with ErrWaitingRoomTimeout if we are not let out of the waiting room in time (default 0, wait forever)
+func WithWaitingRoomTimeout(timeout time.Duration) Option {
+	return func(session *ZoomSession) error {
+		session.waitingRoomTimeout = timeout
+		return nil
+	}
+}
+
+// WithAbortOnHandlerError makes Run leave the meeting and return the *HandlerError when the onMessage function or a handler fails, instead of reporting it to OnError handlers and carrying on
+func WithAbortOnHandlerError(abort bool) Option {
+	return func(session *ZoomSession) error {
+		session.abortOnHandlerError = abort
+		return nil
+	}
+}
+
+// WithLogger sets where the session logs to (default standard error at LevelInfo).  every websocket message is logged at debug level
+func WithLogger(logger Logger) Option {
+	return func(session *ZoomSession) error {
+		session.logger = logger
+		return nil
+	}
+}
+
+// WithRedaction controls whether chat text, tokens and credentials are hidden in logs (default true)
+func WithRedaction(enabled bool) Option {
+	return func(session *ZoomSession) error {
+		session.redact = enabled
+		return nil
+	}
+}
+
+// WithEndpoints changes the zoom web services the session talks to (default DefaultEndpoints())
+func WithEndpoints(endpoints Endpoints) Option {
+	return func(session *ZoomSession) error {
+		session.Endpoints = endpoints
+		return nil
+	}
+}
+
+// WithReconnectPolicy changes how dropped connections are handled (default DefaultReconnectPolicy()).  nil disables reconnecting
+func WithReconnectPolicy(policy *ReconnectPolicy) Option {
+	return func(session *ZoomSession) error {
+		if policy != nil {
+			if err := policy.validate(); err != nil {
+				return err
+			}
+		}
+		session.Reconnect = policy
+		return nil
+	}
+}
+
+// WithBreakoutPolicy makes the session follow the host's breakout room assignments and/or return to the main meeting when rooms close (default neither)
+func WithBreakoutPolicy(policy BreakoutPolicy) Option {
+	return func(session *ZoomSession) error {
+		session.breakoutPolicy = policy
+		return nil
+	}
+}
+
+// WithCookieJar sets the jar cookies from zoom are kept in (default a new, empty one).  Use it to share or persist cookies
+func WithCookieJar(jar http.CookieJar) Option {
+	return func(session *ZoomSession) error {
+		session.cookieJar = &cookieRecorder{CookieJar: jar}
+		return nil
+	}
+}
+
+// WithSigner makes the session get its signatures from signer instead of signing them itself, so WithCredentials isn't needed (but WithSDKKey is)
+func WithSigner(signer Signer) Option {
+	return func(session *ZoomSession) error {
+		session.signer = signer
+		return nil
+	}
+}
+
+// WithSDKKey sets the meeting SDK key without the secret, for use with WithSigner
+func WithSDKKey(key string) Option {
+	return func(session *ZoomSession) error {
+		session.ZoomJwtApiKey = key
+		return nil
+	}
+}
+
+// WithSignatureLifetime sets how long the signatures the session makes itself are valid for (default DefaultSignatureLifetime).  it has no effect with WithSigner
+func WithSignatureLifetime(lifetime time.Duration) Option {
+	return func(session *ZoomSession) error {
+		if lifetime < MinSignatureLifetime || lifetime > MaxSignatureLifetime {
+			return fmt.Errorf("Signature lifetime must be between %s and %s", MinSignatureLifetime, MaxSignatureLifetime)
+		}
+		session.signatureLifetime = lifetime
+		return nil
+	}
+}
+
+// WithZAK makes the session join (or start) the meeting as the host using a ZAK token for the meeting's owner, which can be gotten from the zoom API (GET /users/me/token?type=zak).  the signature is made for the host role too, so a signing service has to allow that
+func WithZAK(zak string) Option {
+	return func(session *ZoomSession) error {
+		session.zak = zak
+		return nil
+	}
+}
+
+// WithEmail sets the email address we join with, for meetings and webinars that ask for one.  for registration required meetings it has to be the one that was registered
+func WithEmail(email string) Option {
+	return func(session *ZoomSession) error {
+		session.email = email
+		return nil
+	}
+}
+
+// WithRegistrantToken sets the registrant token for meetings that require registration.  it is the tk parameter of the join link zoom emails after registering
+func WithRegistrantToken(tk string) Option {
+	return func(session *ZoomSession) error {
+		session.registrantToken = tk
+		return nil
+	}
+}
+
+// WithZTK sets the ZTK (a zoom account token) for meetings that only allow signed in users
+func WithZTK(ztk string) Option {
+	return func(session *ZoomSession) error {
+		session.ztk = ztk
+		return nil
+	}
+}
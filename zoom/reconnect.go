@@ -0,0 +1,140 @@
+package zoom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// ReconnectPolicy controls how Run reestablishes a dropped connection.  Delays grow exponentially from InitialDelay up to MaxDelay and are randomized by up to half to avoid many bots reconnecting in lockstep.
+type ReconnectPolicy struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	MaxAttempts  int // 0 means retry until the context is cancelled or zoom refuses us for good (wrong password, meeting deleted, ...)
+}
+
+func DefaultReconnectPolicy() *ReconnectPolicy {
+	return &ReconnectPolicy{
+		InitialDelay: 1 * time.Second,
+		MaxDelay:     2 * time.Minute,
+		Multiplier:   2,
+	}
+}
+
+// the following are not sent by zoom, they are passed to the onMessage function by Run to report on the state of the connection
+
+// Disconnected is sent when the websocket connection drops unexpectedly
+type Disconnected struct {
+	Err error
+}
+
+// Reconnecting is sent before every reconnection attempt.  Err is the reason the previous attempt failed (nil on the first attempt)
+type Reconnecting struct {
+	Attempt int
+	Delay   time.Duration
+	Err     error
+}
+
+// Reconnected is sent once we have rejoined the meeting as the same participant
+type Reconnected struct {
+	Attempts int
+}
+
+// errors from Connect that trying again won't fix, reconnecting stops as soon as it gets one of these
+var permanentErrors = []error{
+	ErrMeetingNotFound,
+	ErrWrongPassword,
+	ErrSignatureInvalid,
+	ErrRegistrationRequired,
+	ErrLoginRequired,
+	ErrEmailRequired,
+	ErrInvalidMeetingNumber,
+}
+
+func isPermanent(err error) bool {
+	for _, permanent := range permanentErrors {
+		if errors.Is(err, permanent) {
+			return true
+		}
+	}
+	return false
+}
+
+// validate rejects policies that would reconnect in a tight loop
+func (policy *ReconnectPolicy) validate() error {
+	switch {
+	case policy.InitialDelay <= 0:
+		return errors.New("Reconnect policy's initial delay must be positive")
+	case policy.MaxDelay < policy.InitialDelay:
+		return errors.New("Reconnect policy's max delay must be at least its initial delay")
+	case policy.Multiplier < 1:
+		return errors.New("Reconnect policy's multiplier must be at least 1")
+	case policy.MaxAttempts < 0:
+		return errors.New("Reconnect policy's max attempts can't be negative")
+	}
+	return nil
+}
+
+func (policy *ReconnectPolicy) delay(attempt int) time.Duration {
+	// policies set directly on session.Reconnect skip WithReconnectPolicy's checks, so don't trust them to make sense
+	delay := float64(policy.InitialDelay)
+	if delay <= 0 {
+		delay = float64(DefaultReconnectPolicy().InitialDelay)
+	}
+	maxDelay := float64(policy.MaxDelay)
+	if maxDelay < delay {
+		maxDelay = delay
+	}
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= multiplier
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	// randomly pick something between half the delay and the full delay
+	half := int64(delay / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// reconnect gets fresh tokens and dials a new websocket, rejoining with our previous zoom ID and participant ID so zoom treats us as the same participant.
+// it gives up straight away if zoom says we can't join at all (see permanentErrors)
+func (session *ZoomSession) reconnect(ctx context.Context, onMessageFunction onMessage, cause error) error {
+	policy := session.Reconnect
+	session.logger.Warn("Disconnected", "error", cause)
+	session.dispatch(onMessageFunction, &Disconnected{Err: cause})
+	session.setState(onMessageFunction, StateDisconnected)
+
+	var lastErr error
+	for attempt := 1; policy.MaxAttempts == 0 || attempt <= policy.MaxAttempts; attempt++ {
+		delay := policy.delay(attempt)
+		session.logger.Info("Reconnecting", "attempt", attempt, "delay", delay, "error", lastErr)
+		session.dispatch(onMessageFunction, &Reconnecting{Attempt: attempt, Delay: delay, Err: lastErr})
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		session.setState(onMessageFunction, StateConnecting)
+		if lastErr = session.connect(ctx, true); lastErr == nil {
+			session.logger.Info("Reconnected", "attempts", attempt)
+			session.dispatch(onMessageFunction, &Reconnected{Attempts: attempt})
+			return nil
+		}
+		if isPermanent(lastErr) {
+			session.logger.Warn("Giving up reconnecting", "attempt", attempt, "error", lastErr)
+			return fmt.Errorf("Failed to reconnect: %w", lastErr)
+		}
+	}
+	return fmt.Errorf("Failed to reconnect after %d attempts: %w", policy.MaxAttempts, lastErr)
+}
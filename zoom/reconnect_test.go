@@ -0,0 +1,155 @@
+package zoom_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+func TestReconnectRejoinsAsSameParticipant(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	reconnected := make(chan *zoom.Reconnected, 1)
+	session.OnReconnected(func(session *zoom.ZoomSession, message *zoom.Reconnected) error {
+		reconnected <- message
+		return nil
+	})
+	runErr := startSession(t, session, nil)
+
+	server.DropConnections()
+	waitForConnection(t, server, 2)
+	select {
+	case <-reconnected:
+	case <-time.After(testTimeout):
+		t.Fatal("OnReconnected was not called")
+	}
+	waitForState(t, session, zoom.StateInMeeting)
+
+	join := server.Joins()[1]
+	if join.Get("zoomid") != server.JoinResponse.ZoomID {
+		t.Errorf("Rejoined with zoomid %q, wanted %q", join.Get("zoomid"), server.JoinResponse.ZoomID)
+	}
+	if join.Get("participantID") != strconv.Itoa(server.JoinResponse.ParticipantID) {
+		t.Errorf("Rejoined with participantID %q, wanted %d", join.Get("participantID"), server.JoinResponse.ParticipantID)
+	}
+
+	leave(t, session, runErr)
+}
+
+func TestRunReturnsDropWithoutReconnectPolicy(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server, zoom.WithReconnectPolicy(nil))
+
+	runErr := startSession(t, session, nil)
+	server.DropConnections()
+	if err := waitForRun(t, runErr); err == nil {
+		t.Fatal("Run returned nil after the connection dropped")
+	}
+	if joins := len(server.Joins()); joins != 1 {
+		t.Errorf("Connected %d times, wanted 1", joins)
+	}
+}
+
+func TestSendWhileReconnecting(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+	runErr := startSession(t, session, nil)
+
+	// run with -race, sending has to be safe while Run swaps the connection
+	stop := make(chan struct{})
+	sent := make(chan struct{})
+	go func() {
+		defer close(sent)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			session.SendChatMessage(zoom.EVERYONE_CHAT_ID, "hello")
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	for i := 2; i <= 4; i++ {
+		server.DropConnections()
+		waitForConnection(t, server, i)
+		waitForState(t, session, zoom.StateInMeeting)
+	}
+	close(stop)
+	<-sent
+
+	leave(t, session, runErr)
+}
+
+func TestReconnectStopsOnPermanentError(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	var attempts int32
+	session.OnReconnecting(func(session *zoom.ZoomSession, message *zoom.Reconnecting) error {
+		atomic.AddInt32(&attempts, 1)
+		return nil
+	})
+	runErr := startSession(t, session, nil)
+
+	// the meeting was deleted while we were in it
+	server.SetInfoError(3001, "Meeting does not exist")
+	server.DropConnections()
+	err := waitForRun(t, runErr)
+	if !errors.Is(err, zoom.ErrMeetingNotFound) {
+		t.Fatalf("Run returned %v, wanted ErrMeetingNotFound", err)
+	}
+	if n := atomic.LoadInt32(&attempts); n != 1 {
+		t.Errorf("Tried to reconnect %d times", n)
+	}
+}
+
+func TestReconnectPolicyValidation(t *testing.T) {
+	for _, policy := range []*zoom.ReconnectPolicy{
+		{MaxAttempts: 3},
+		{InitialDelay: -time.Second, MaxDelay: time.Second, Multiplier: 2},
+		{InitialDelay: time.Second, MaxDelay: time.Millisecond, Multiplier: 2},
+		{InitialDelay: time.Second, MaxDelay: time.Minute, Multiplier: 0.5},
+		{InitialDelay: time.Second, MaxDelay: time.Minute, Multiplier: 2, MaxAttempts: -1},
+	} {
+		if _, err := zoom.New(zoomtest.MeetingNumber, zoom.WithCredentials("testKey", "testSecret"), zoom.WithReconnectPolicy(policy)); err == nil {
+			t.Errorf("Policy %+v was accepted", policy)
+		}
+	}
+	for _, policy := range []*zoom.ReconnectPolicy{nil, zoom.DefaultReconnectPolicy()} {
+		if _, err := zoom.New(zoomtest.MeetingNumber, zoom.WithCredentials("testKey", "testSecret"), zoom.WithReconnectPolicy(policy)); err != nil {
+			t.Errorf("Policy %+v was refused: %v", policy, err)
+		}
+	}
+}
+
+func TestReconnectKeepsNewName(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	runErr := startSession(t, session, nil)
+	if err := session.RenameMe("Renamed Bot"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, server, zoom.WS_CONF_RENAME_REQ)
+
+	server.DropConnections()
+	waitForConnection(t, server, 2)
+	if dn2 := server.Joins()[1].Get("dn2"); dn2 != base64.StdEncoding.EncodeToString([]byte("Renamed Bot")) {
+		t.Errorf("Rejoined with dn2 %q, wanted the new name", dn2)
+	}
+
+	leave(t, session, runErr)
+}
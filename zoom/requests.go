@@ -1,19 +1,34 @@
 package zoom
 
+import "context"
+
 func (session *ZoomSession) SendChatMessage(destNodeID int, text string) error {
-	return session.SendMessage(session.websocketConnection, WS_CONF_CHAT_REQ, ConferenceChatRequest{
+	return session.send(WS_CONF_CHAT_REQ, ConferenceChatRequest{
 		DestNodeID: destNodeID,
-		Sn:         []byte(session.JoinInfo.ZoomID),
+		Sn:         []byte(session.CurrentJoinInfo().ZoomID),
 		Text:       []byte(text),
 	})
 }
 
 // host required
 func (session *ZoomSession) RequestBreakoutRoomToken(topic string, index int) error {
-	return session.SendMessage(session.websocketConnection, WS_CONF_BO_TOKEN_BATCH_REQ, ConferenceBreakoutRoomTokenBatchRequest{
+	return session.send(WS_CONF_BO_TOKEN_BATCH_REQ, ConferenceBreakoutRoomTokenBatchRequest{
+		Topic: topic,
+		Index: index,
+	})
+}
+
+// host required
+// same as RequestBreakoutRoomToken but waits for the WS_CONF_BO_TOKEN_RES
+func (session *ZoomSession) BreakoutRoomToken(ctx context.Context, topic string, index int) (*ConferenceBreakoutRoomTokenResponse, error) {
+	response, err := session.SendAndWait(ctx, WS_CONF_BO_TOKEN_BATCH_REQ, ConferenceBreakoutRoomTokenBatchRequest{
 		Topic: topic,
 		Index: index,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return response.(*ConferenceBreakoutRoomTokenResponse), nil
 }
 
 // host required
@@ -31,33 +46,44 @@ func (session *ZoomSession) CreateBreakoutRoom(rooms []BreakoutRoomItem, autoJoi
 		StartTimeOnMMR: 464,
 		ItemList:       rooms,
 	}
-	return session.SendMessage(session.websocketConnection, WS_CONF_BO_START_REQ, ConferenceBreakoutRoomStartRequest{
+	return session.send(WS_CONF_BO_START_REQ, ConferenceBreakoutRoomStartRequest{
 		Proto: ConferenceBreakoutRoomAttributeIndicationDataAlias(protoData),
 	})
 }
 
 // host required
 func (session *ZoomSession) BreakoutRoomBroadcast(text string) error {
-	return session.SendMessage(session.websocketConnection, WS_CONF_BO_BROADCAST_REQ, ConferenceBreakoutRoomBroadcastRequest{
+	return session.send(WS_CONF_BO_BROADCAST_REQ, ConferenceBreakoutRoomBroadcastRequest{
 		TextContent: []byte(text),
 	})
 }
 
 /*
-breakout room joining is not fully implemented
+only asks for the token, use JoinBreakoutRoom to actually move into the room
 you have to send the WS_CONF_BO_JOIN_REQ (which this function does), wait for the WS_CONF_BO_JOIN_RES, then make a separate websocket connection using the token you get
 breakout rooms are basically meetings= within meetings
 */
 func (session *ZoomSession) RequestBreakoutRoomJoinToken(targetBID string) error {
-	return session.SendMessage(session.websocketConnection, WS_CONF_BO_JOIN_REQ, ConferenceBreakoutRoomJoinRequest{
+	return session.send(WS_CONF_BO_JOIN_REQ, ConferenceBreakoutRoomJoinRequest{
 		TargetBID: targetBID,
 	})
 }
 
+// same as RequestBreakoutRoomJoinToken but waits for the WS_CONF_BO_JOIN_RES
+func (session *ZoomSession) JoinBreakoutRoomToken(ctx context.Context, targetBID string) (*ConferenceBreakoutRoomJoinResponse, error) {
+	response, err := session.SendAndWait(ctx, WS_CONF_BO_JOIN_REQ, ConferenceBreakoutRoomJoinRequest{
+		TargetBID: targetBID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.(*ConferenceBreakoutRoomJoinResponse), nil
+}
+
 // equivalent to zoom "join audio" - basically just allows us to have the voice icon next to our name
 func (session *ZoomSession) JoinAudioVoipChannel(status bool) error {
-	return session.SendMessage(session.websocketConnection, WS_AUDIO_VOIP_JOIN_CHANNEL_REQ, AudioVoipJoinChannelRequest{
-		ID:  session.JoinInfo.UserID,
+	return session.send(WS_AUDIO_VOIP_JOIN_CHANNEL_REQ, AudioVoipJoinChannelRequest{
+		ID:  session.CurrentJoinInfo().UserID,
 		BOn: status,
 	})
 }
@@ -65,8 +91,8 @@ func (session *ZoomSession) JoinAudioVoipChannel(status bool) error {
 // NOTE: this does not actually allow you to screenshare, that has yet to implemented.  it just changes the indicator next to your name and will show that you have solid black video
 // true for mute, false for unmute
 func (session *ZoomSession) SetVideoMuted(status bool) error {
-	return session.SendMessage(session.websocketConnection, WS_VIDEO_MUTE_VIDEO_REQ, VideoMuteRequest{
-		ID:  session.JoinInfo.UserID,
+	return session.send(WS_VIDEO_MUTE_VIDEO_REQ, VideoMuteRequest{
+		ID:  session.CurrentJoinInfo().UserID,
 		BOn: status,
 	})
 }
@@ -74,8 +100,8 @@ func (session *ZoomSession) SetVideoMuted(status bool) error {
 // NOTE: this does not actually allow you to screenshare, that has yet to implemented.  it will show that you are sharing your screen but the output will be black
 // true for mute, false for unmute
 func (session *ZoomSession) SetScreenShareMuted(status bool) error {
-	return session.SendMessage(session.websocketConnection, WS_CONF_SET_SHARE_STATUS_REQ, SetShareStatusRequest{
-		ID:  session.JoinInfo.UserID,
+	return session.send(WS_CONF_SET_SHARE_STATUS_REQ, SetShareStatusRequest{
+		ID:  session.CurrentJoinInfo().UserID,
 		BOn: status,
 	})
 }
@@ -87,61 +113,63 @@ func (session *ZoomSession) SetAudioMuted(status bool) error {
 	if err := session.JoinAudioVoipChannel(true); err != nil {
 		return err
 	}
-	return session.SendMessage(session.websocketConnection, WS_AUDIO_MUTE_REQ, AudioMuteRequest{
+	return session.send(WS_AUDIO_MUTE_REQ, AudioMuteRequest{
 		BMute: status,
 	})
 }
 
 func (session *ZoomSession) RenameMe(newName string) error {
-	return session.RenameById(session.JoinInfo.UserID, session.Username, newName)
+	return session.RenameById(session.CurrentJoinInfo().UserID, session.CurrentUsername(), newName)
 }
 
 // host required to rename others (not self)
 func (session *ZoomSession) RenameById(id int, oldName string, newName string) error {
-	if err := session.SendMessage(session.websocketConnection, WS_CONF_RENAME_REQ, ConferenceRenameRequest{
+	if err := session.send(WS_CONF_RENAME_REQ, ConferenceRenameRequest{
 		ID:     id,
 		Dn2:    []byte(newName),
 		Olddn2: []byte(oldName),
 	}); err != nil {
 		return err
 	}
-	if id == session.JoinInfo.UserID {
+	if id == session.CurrentJoinInfo().UserID {
+		session.mu.Lock()
 		session.Username = newName
+		session.mu.Unlock()
 	}
 	return nil
 }
 
 // host required
 func (session *ZoomSession) RequestAllMute() error {
-	return session.SendMessage(session.websocketConnection, WS_AUDIO_MUTEALL_REQ, AudioMuteAllRequest{
+	return session.send(WS_AUDIO_MUTEALL_REQ, AudioMuteAllRequest{
 		BMute: true,
 	})
 }
 
 // host required
 func (session *ZoomSession) SetMuteUponEntry(status bool) error {
-	return session.SendMessage(session.websocketConnection, WS_CONF_SET_MUTE_UPON_ENTRY_REQ, ConferenceSetMuteUponEntryRequest{
+	return session.send(WS_CONF_SET_MUTE_UPON_ENTRY_REQ, ConferenceSetMuteUponEntryRequest{
 		BOn: status,
 	})
 }
 
 // host required
 func (session *ZoomSession) SetAllowUnmuteAudio(status bool) error {
-	return session.SendMessage(session.websocketConnection, WS_CONF_ALLOW_UNMUTE_AUDIO_REQ, ConferenceAllowUnmuteAudioRequest{
+	return session.send(WS_CONF_ALLOW_UNMUTE_AUDIO_REQ, ConferenceAllowUnmuteAudioRequest{
 		BOn: true,
 	})
 }
 
 // host required
 func (session *ZoomSession) SetAllowParticipantRename(status bool) error {
-	return session.SendMessage(session.websocketConnection, WS_CONF_ALLOW_PARTICIPANT_RENAME_REQ, ConferenceAllowParticipantRenameRequest{
+	return session.send(WS_CONF_ALLOW_PARTICIPANT_RENAME_REQ, ConferenceAllowParticipantRenameRequest{
 		BOn: true,
 	})
 }
 
 // host required
 func (session *ZoomSession) SetAllowUnmuteVideo(status bool) error {
-	return session.SendMessage(session.websocketConnection, WS_CONF_ALLOW_UNMUTE_VIDEO_REQ, ConferenceAllowUnmuteVideoRequest{
+	return session.send(WS_CONF_ALLOW_UNMUTE_VIDEO_REQ, ConferenceAllowUnmuteVideoRequest{
 		BOn: true,
 	})
 }
@@ -149,7 +177,7 @@ func (session *ZoomSession) SetAllowUnmuteVideo(status bool) error {
 // host required
 // possible values: CHAT_EVERYONE_PUBLICLY_PRIVATELY = 1, CHAT_HOST_ONLY = 3, CHAT_NO_ONE = 4, CHAT_EVERYONE_PUBLICLY = 5
 func (session *ZoomSession) SetChatLevel(status int) error {
-	return session.SendMessage(session.websocketConnection, WS_CONF_CHAT_PRIVILEDGE_REQ, ConferenceChatPrivilegeRequest{
+	return session.send(WS_CONF_CHAT_PRIVILEDGE_REQ, ConferenceChatPrivilegeRequest{
 		ChatPriviledge: status,
 	})
 }
@@ -166,12 +194,48 @@ CMM_SHARE_SETTING_ANYONE_GRAB = 2 (How many participants can share at the same t
 CMM_SHARE_SETTING_MULTI_SHARE = 3 (How many participants can share at the same time? Multiple participants can share simultaneously)
 */
 func (session *ZoomSession) SetShareLockedStatus(status int) error {
-	return session.SendMessage(session.websocketConnection, WS_CONF_LOCK_SHARE_REQ, ConferenceLockShareRequest{
+	return session.send(WS_CONF_LOCK_SHARE_REQ, ConferenceLockShareRequest{
 		LockShare: status,
 	})
 }
 
 // host required
 func (session *ZoomSession) EndMeeting() error {
-	return session.SendMessage(session.websocketConnection, WS_CONF_END_REQ, ConferenceEndRequest{})
+	return session.send(WS_CONF_END_REQ, ConferenceEndRequest{})
+}
+
+// host required
+// webinars only.  makes an attendee a panelist and waits for the WS_CONF_ROLE_CHANGE_RES
+func (session *ZoomSession) PromoteToPanelist(ctx context.Context, userID int) (*ConferenceRoleChangeResponse, error) {
+	return session.changeWebinarRole(ctx, userID, WebinarRolePanelist)
+}
+
+// host required
+// webinars only.  makes a panelist an attendee again and waits for the WS_CONF_ROLE_CHANGE_RES
+func (session *ZoomSession) DemoteToAttendee(ctx context.Context, userID int) (*ConferenceRoleChangeResponse, error) {
+	return session.changeWebinarRole(ctx, userID, WebinarRoleAttendee)
+}
+
+func (session *ZoomSession) changeWebinarRole(ctx context.Context, userID int, role int) (*ConferenceRoleChangeResponse, error) {
+	response, err := session.SendAndWait(ctx, WS_CONF_ROLE_CHANGE_REQ, ConferenceRoleChangeRequest{
+		ID:   userID,
+		Role: role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.(*ConferenceRoleChangeResponse), nil
+}
+
+// host required
+// webinars only.  lets an attendee unmute (or stops them) without making them a panelist and waits for the WS_AUDIO_ALLOW_TALK_RES
+func (session *ZoomSession) AllowAttendeeTalk(ctx context.Context, userID int, allow bool) (*AudioAllowTalkResponse, error) {
+	response, err := session.SendAndWait(ctx, WS_AUDIO_ALLOW_TALK_REQ, AudioAllowTalkRequest{
+		ID:         userID,
+		BAllowTalk: allow,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.(*AudioAllowTalkResponse), nil
 }
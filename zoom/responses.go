@@ -0,0 +1,138 @@
+package zoom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// returned by SendAndWait when the connection goes away before the response arrives
+var ErrConnectionClosed = errors.New("Connection closed")
+
+// returned when sending before Connect
+var ErrNotConnected = errors.New("Not connected")
+
+// most responses are named the same as their request with _RES instead of _REQ, these are the exceptions
+var responseNameOverrides = map[int]int{
+	WS_CONF_BO_TOKEN_BATCH_REQ: WS_CONF_BO_TOKEN_RES,
+}
+
+// maps request event numbers to the event number of their response.  only responses with a type in msgTypes are included, SendAndWait couldn't return the others
+var requestToResponse = func() map[int]int {
+	nameToNumber := make(map[string]int, len(MessageNumberToName))
+	for number, name := range MessageNumberToName {
+		nameToNumber[name] = number
+	}
+
+	pairs := make(map[int]int)
+	for number, name := range MessageNumberToName {
+		if !strings.HasSuffix(name, "_REQ") {
+			continue
+		}
+		if response, ok := nameToNumber[strings.TrimSuffix(name, "_REQ")+"_RES"]; ok && msgTypes[response] != nil {
+			pairs[number] = response
+		}
+	}
+	for request, response := range responseNameOverrides {
+		pairs[request] = response
+	}
+	return pairs
+}()
+
+type pendingResponse struct {
+	message *GenericZoomMessage
+	err     error
+}
+
+// pendingRequests holds the callers of SendAndWait that are waiting on a response, keyed by the response event number.
+// zoom responses carry nothing to tie them to a specific request so they are handed out first come first served
+type pendingRequests struct {
+	mu      sync.Mutex
+	waiters map[int][]chan pendingResponse
+}
+
+func (pending *pendingRequests) add(responseEvt int) chan pendingResponse {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+
+	if pending.waiters == nil {
+		pending.waiters = make(map[int][]chan pendingResponse)
+	}
+	waiter := make(chan pendingResponse, 1)
+	pending.waiters[responseEvt] = append(pending.waiters[responseEvt], waiter)
+	return waiter
+}
+
+func (pending *pendingRequests) remove(responseEvt int, waiter chan pendingResponse) {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+
+	waiters := pending.waiters[responseEvt]
+	for i := range waiters {
+		if waiters[i] == waiter {
+			pending.waiters[responseEvt] = append(waiters[:i], waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// resolve hands message to the oldest caller waiting on its event number
+func (pending *pendingRequests) resolve(message *GenericZoomMessage) {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+
+	waiters := pending.waiters[message.Evt]
+	if len(waiters) == 0 {
+		return
+	}
+	waiters[0] <- pendingResponse{message: message}
+	pending.waiters[message.Evt] = waiters[1:]
+}
+
+// fail fails every waiting caller, used when the connection goes away
+func (pending *pendingRequests) fail(err error) {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+
+	for _, waiters := range pending.waiters {
+		for _, waiter := range waiters {
+			waiter <- pendingResponse{err: err}
+		}
+	}
+	pending.waiters = nil
+}
+
+// SendAndWait sends a request and waits for its response, returning the decoded response body (see msgTypes in message.go).
+// Requests whose response has no type there fail without being sent, use SendMessage for those.
+// If ctx has no deadline the wait is limited to the response timeout (see WithResponseTimeout).  It can be called from handlers, see Run.
+func (session *ZoomSession) SendAndWait(ctx context.Context, eventNumber int, body interface{}) (Message, error) {
+	responseEvt, ok := requestToResponse[eventNumber]
+	if !ok {
+		return nil, fmt.Errorf("No response is known for message %s (%d)", MessageNumberToName[eventNumber], eventNumber)
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, session.responseTimeout)
+		defer cancel()
+	}
+
+	// register before sending so we can't miss a quick response
+	waiter := session.pending.add(responseEvt)
+	if err := session.send(eventNumber, body); err != nil {
+		session.pending.remove(responseEvt, waiter)
+		return nil, err
+	}
+
+	select {
+	case response := <-waiter:
+		if response.err != nil {
+			return nil, response.err
+		}
+		return GetMessageBody(response.message)
+	case <-ctx.Done():
+		session.pending.remove(responseEvt, waiter)
+		return nil, fmt.Errorf("Waiting for %s: %w", MessageNumberToName[responseEvt], ctx.Err())
+	}
+}
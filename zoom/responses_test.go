@@ -0,0 +1,79 @@
+package zoom_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+func TestSendAndWait(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+	runErr := startSession(t, session, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	response, err := session.JoinBreakoutRoomToken(ctx, "fakeBID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Botoken != zoomtest.BreakoutToken("fakeBID") {
+		t.Errorf("Got token %q", response.Botoken)
+	}
+
+	// zoom answers this one but we have no type for the answer
+	sent := len(server.Received())
+	if _, err := session.SendAndWait(ctx, zoom.WS_CONF_END_REQ, zoom.ConferenceEndRequest{}); err == nil {
+		t.Errorf("SendAndWait with an untyped response succeeded")
+	}
+	if len(server.Received()) != sent {
+		t.Errorf("SendAndWait sent a request it can't get the response to")
+	}
+
+	// the server doesn't answer this one
+	shortCtx, cancelShort := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelShort()
+	if _, err := session.BreakoutRoomToken(shortCtx, "topic", 0); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("BreakoutRoomToken without a response returned %v", err)
+	}
+
+	leave(t, session, runErr)
+}
+
+func TestSendAndWaitFromHandler(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	type result struct {
+		response *zoom.ConferenceBreakoutRoomJoinResponse
+		err      error
+	}
+	results := make(chan result, 1)
+	session.OnChat(func(session *zoom.ZoomSession, message *zoom.ConferenceChatIndication) error {
+		response, err := session.JoinBreakoutRoomToken(context.Background(), string(message.Text))
+		results <- result{response, err}
+		return nil
+	})
+	runErr := startSession(t, session, nil)
+
+	server.Send(zoom.WS_CONF_CHAT_INDICATION, zoom.ConferenceChatIndication{Text: []byte("fakeBID")})
+	select {
+	case result := <-results:
+		if result.err != nil {
+			t.Fatal(result.err)
+		}
+		if result.response.Botoken != zoomtest.BreakoutToken("fakeBID") {
+			t.Errorf("Got token %q", result.response.Botoken)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("SendAndWait from a handler did not return")
+	}
+
+	leave(t, session, runErr)
+}
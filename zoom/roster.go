@@ -0,0 +1,55 @@
+package zoom
+
+import (
+	"sort"
+)
+
+// Roster returns everyone we know to be in the room we are in (including us), sorted by ID.  it is built from the WS_CONF_ROSTER_INDICATION messages zoom sends
+func (session *ZoomSession) Roster() []RosterAddItem {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	roster := make([]RosterAddItem, 0, len(session.roster))
+	for _, person := range session.roster {
+		roster = append(roster, person)
+	}
+	sort.Slice(roster, func(i, j int) bool {
+		return roster[i].ID < roster[j].ID
+	})
+	return roster
+}
+
+// updateRoster applies a roster indication to the roster cache
+func (session *ZoomSession) updateRoster(indication *ConferenceRosterIndication) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if session.roster == nil {
+		session.roster = make(map[int]RosterAddItem)
+	}
+	for _, person := range indication.Add {
+		session.roster[person.ID] = person
+	}
+	for _, update := range indication.Update {
+		person, ok := session.roster[update.ID]
+		if !ok {
+			continue
+		}
+		// updates only have the fields that changed
+		if len(update.Dn2) > 0 {
+			person.Dn2 = update.Dn2
+		}
+		if update.Role != 0 {
+			person.Role = update.Role
+		}
+		session.roster[update.ID] = person
+	}
+	for _, remove := range indication.Remove {
+		delete(session.roster, remove.ID)
+	}
+}
+
+// clearRoster forgets everyone, for when we move to another room and zoom sends us its roster instead
+func (session *ZoomSession) clearRoster() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.roster = nil
+}
@@ -1,12 +1,17 @@
 package zoom
 
 import (
+	"context"
 	"crypto/tls"
 	"errors"
+	"log"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
+	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,59 +21,158 @@ import (
 type ZoomSession struct {
 	mu sync.Mutex
 
-	MeetingNumber    string
-	MeetingPassword  string
-	Username         string
-	HardwareID       uuid.UUID
-	ZoomJwtApiKey    string
-	ZoomJwtApiSecret string
-	JoinInfo         JoinConferenceResponse
-	ProxyURL         *url.URL
+	MeetingNumber     string
+	MeetingPassword   string
+	Username          string
+	HardwareID        uuid.UUID
+	ZoomJwtApiKey     string
+	ZoomJwtApiSecret  string
+	JoinInfo          JoinConferenceResponse
+	ProxyURL          *url.URL
+	Reconnect         *ReconnectPolicy // nil disables reconnecting
+	Endpoints         Endpoints
+	signer            Signer
+	signatureLifetime time.Duration
+	zak               string
+	email             string
+	registrantToken   string
+	ztk               string
+	isWebinar         bool
+	webinarRole       int
+
+	userAgent        UserAgent
+	sdkVersion       string
+	language         string
+	tlsConfig        *tls.Config
+	httpTimeout      time.Duration
+	handshakeTimeout time.Duration
+	responseTimeout  time.Duration
+	keepalive        time.Duration
+	staleTimeout     time.Duration
+	logger           Logger
+	redact           bool
 
 	meetingOpt          string
+	mainMeetingOpt      string // meetingOpt to go back to when leaving a breakout room
+	breakoutBID         string
+	breakoutTarget      string // BID of the assignment we are following, if any
+	roomSwitches        chan chan error
+	breakoutPolicy      BreakoutPolicy
+	breakoutCloseTimer  *time.Timer
+	roster              map[int]RosterAddItem
+	cookieJar           *cookieRecorder
+	resume              bool
 	httpClient          *http.Client
 	websocketConnection *websocket.Conn
 	sendSequenceNumber  uint32
+	handlers            handlerRegistry
+	dispatcher          *dispatcher
+	pending             pendingRequests
+	cancelRun           context.CancelFunc
+	runDone             chan struct{}
+	left                bool
+	state               State
+	waitingRoomTimeout  time.Duration
+	abortOnHandlerError bool
+	lastKeepalive       atomic.Value // time.Time
 }
 
-func NewZoomSession(meetingNumber string, meetingPassword string, username string, hardwareID string, proxyURL string, zoomJwtApiKey string, zoomJwtApiSecret string) (*ZoomSession, error) {
-	if meetingNumber == "" || meetingPassword == "" || username == "" || hardwareID == "" || zoomJwtApiKey == "" || zoomJwtApiSecret == "" {
-		return nil, errors.New("Please make sure to provide values for meeting number, meeting password, username, hardware ID (hardware ID must be in the format of UUID), and API key/secret.")
+// sessions share this unless given their own with WithTLSConfig or WithDebugProxy, neither of which modify it.  Manager relies on that to tell which sessions can use its http client
+var defaultTLSConfig = &tls.Config{
+	MinVersion: tls.VersionTLS12,
+}
+
+// New creates a session for joining the meeting with the given number.  At least WithCredentials is required, see options.go for the rest.
+// Meetings without a password can be joined by leaving out WithPassword.
+func New(meetingNumber string, options ...Option) (*ZoomSession, error) {
+	session := ZoomSession{
+		MeetingNumber: strings.Replace(meetingNumber, " ", "", -1), // remove all
+		Username:      "Bot",
+		// this is a hardware id.  you shouldnt have it change a bunch of times per ip or you will look highly suspicious, so set it with WithHardwareID if you are running more than once
+		HardwareID: uuid.New(),
+		Reconnect:  DefaultReconnectPolicy(),
+		Endpoints:  DefaultEndpoints(),
+
+		userAgent:        DefaultUserAgent,
+		sdkVersion:       DefaultSDKVersion,
+		language:         DefaultLanguage,
+		tlsConfig:        defaultTLSConfig,
+		httpTimeout:      35 * time.Second, // largeish timeout for slow proxies
+		handshakeTimeout: 35 * time.Second,
+		responseTimeout:  15 * time.Second,
+		// zoom sends pings (aside from regular websocket ones) approximately every minute of the form "{"evt":0,"seq":74}"
+		keepalive:    60 * time.Second,
+		staleTimeout: 3 * time.Minute,
+		logger:       NewStdLogger(log.New(os.Stderr, "", log.LstdFlags), LevelInfo),
+		redact:       true,
+
+		roomSwitches: make(chan chan error),
 	}
-	uuidParsed, err := uuid.Parse(hardwareID)
-	if err != nil {
+	for _, option := range options {
+		if err := option(&session); err != nil {
+			return nil, err
+		}
+	}
+
+	if session.MeetingNumber == "" || session.Username == "" || session.ZoomJwtApiKey == "" || (session.ZoomJwtApiSecret == "" && session.signer == nil) {
+		return nil, errors.New("Please make sure to provide values for meeting number, username and API key/secret (or API key and a signer).")
+	}
+	if err := validateMeetingNumber(session.MeetingNumber); err != nil {
 		return nil, err
 	}
-	session := ZoomSession{
-		MeetingNumber:    strings.Replace(meetingNumber, " ", "", -1), // remove all
-		MeetingPassword:  meetingPassword,
-		Username:         username,
-		HardwareID:       uuidParsed,
-		ZoomJwtApiKey:    zoomJwtApiKey,
-		ZoomJwtApiSecret: zoomJwtApiSecret,
+	if session.signer == nil {
+		session.signer = &HMACSigner{Key: session.ZoomJwtApiKey, Secret: session.ZoomJwtApiSecret, Lifetime: session.signatureLifetime}
 	}
 
-	session.httpClient = &http.Client{
-		Timeout: 35 * time.Second, // largeish timeout for slow proxies
+	if session.httpClient == nil {
+		transport := &http.Transport{
+			TLSClientConfig:    session.tlsConfig,
+			DisableCompression: false,
+			DisableKeepAlives:  false,
+		}
+		if session.ProxyURL != nil {
+			transport.Proxy = http.ProxyURL(session.ProxyURL)
+		}
+		session.httpClient = &http.Client{
+			Timeout:   session.httpTimeout,
+			Transport: transport,
+		}
 	}
 
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true, // ignore certificate errors so we can use charles to debug
-		},
-		DisableCompression: false,
-		DisableKeepAlives:  false,
-	}
-	if proxyURL != "" {
-		proxyUrlParsed, err := url.Parse(proxyURL)
-		if err != nil {
-			return nil, err
+	// the info request, the rwg ping and the websocket all share one jar so zoom sees the same cookies everywhere, like in a browser
+	if session.cookieJar == nil {
+		jar := session.httpClient.Jar
+		if jar == nil {
+			// only fails when given options
+			jar, _ = cookiejar.New(nil)
 		}
-		// save for websocket client later
-		session.ProxyURL = proxyUrlParsed
-		transport.Proxy = http.ProxyURL(session.ProxyURL)
+		session.cookieJar = &cookieRecorder{CookieJar: jar}
+	}
+	if session.httpClient.Jar != session.cookieJar {
+		// copy so we don't change a client the caller may be using for other things
+		client := *session.httpClient
+		client.Jar = session.cookieJar
+		session.httpClient = &client
 	}
-	session.httpClient.Transport = transport
 
 	return &session, nil
 }
+
+// NewZoomSession creates a session from positional arguments.  proxyURL and meetingPassword may be empty.
+//
+// Deprecated: use New, which takes options and can be extended without breaking callers.
+func NewZoomSession(meetingNumber string, meetingPassword string, username string, hardwareID string, proxyURL string, zoomJwtApiKey string, zoomJwtApiSecret string) (*ZoomSession, error) {
+	if hardwareID == "" {
+		return nil, errors.New("Please make sure to provide values for meeting number, username, hardware ID (hardware ID must be in the format of UUID), and API key/secret.")
+	}
+	options := []Option{
+		WithPassword(meetingPassword),
+		WithDisplayName(username),
+		WithHardwareID(hardwareID),
+		WithCredentials(zoomJwtApiKey, zoomJwtApiSecret),
+	}
+	if proxyURL != "" {
+		options = append(options, WithProxy(proxyURL))
+	}
+	return New(meetingNumber, options...)
+}
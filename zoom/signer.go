@@ -0,0 +1,186 @@
+package zoom
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// roles a signature can be for
+const (
+	RoleAttendee = 0
+	RoleHost     = 1
+)
+
+// zoom wants signatures to last at least half an hour and at most two days
+const (
+	MinSignatureLifetime     = 30 * time.Minute
+	MaxSignatureLifetime     = 48 * time.Hour
+	DefaultSignatureLifetime = 2 * time.Hour
+)
+
+// returned by New and HMACSigner.Sign for meeting numbers that aren't 9 to 11 digits
+var ErrInvalidMeetingNumber = errors.New("Invalid meeting number")
+
+// Signer makes the Meeting SDK signature (a JWT) that the meeting info request is authenticated with.  role is RoleAttendee or RoleHost
+type Signer interface {
+	Sign(ctx context.Context, meetingNumber string, role int) (string, error)
+}
+
+// HMACSigner signs locally with the SDK key and secret.  This is what sessions use by default, but it means the secret has to be on every machine running a bot.  See HTTPSigner for keeping it somewhere else.
+type HMACSigner struct {
+	Key      string
+	Secret   string
+	Lifetime time.Duration // how long signatures are valid for, DefaultSignatureLifetime if 0
+}
+
+// the claims the current web sdk puts in its signatures.  appKey is the old name for sdkKey, both are sent so older and newer sdk apps work
+type signatureClaims struct {
+	AppKey        string      `json:"appKey"`
+	SDKKey        string      `json:"sdkKey"`
+	MeetingNumber json.Number `json:"mn"`
+	Role          int         `json:"role"`
+	Iat           int64       `json:"iat"`
+	Exp           int64       `json:"exp"`
+	TokenExp      int64       `json:"tokenExp"`
+}
+
+func (signer *HMACSigner) Sign(ctx context.Context, meetingNumber string, role int) (string, error) {
+	if err := validateMeetingNumber(meetingNumber); err != nil {
+		return "", err
+	}
+	if role != RoleAttendee && role != RoleHost {
+		return "", fmt.Errorf("Invalid role %d", role)
+	}
+	lifetime := signer.Lifetime
+	if lifetime == 0 {
+		lifetime = DefaultSignatureLifetime
+	}
+	if lifetime < MinSignatureLifetime || lifetime > MaxSignatureLifetime {
+		return "", fmt.Errorf("Signature lifetime %s is outside of what zoom accepts (%s to %s)", lifetime, MinSignatureLifetime, MaxSignatureLifetime)
+	}
+
+	// backdated a little in case our clock is ahead of zoom's
+	iat := time.Now().Unix() - 30
+	exp := iat + int64(lifetime/time.Second)
+	header, err := json.Marshal(map[string]string{"alg": "HS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(signatureClaims{
+		AppKey:        signer.Key,
+		SDKKey:        signer.Key,
+		MeetingNumber: json.Number(meetingNumber),
+		Role:          role,
+		Iat:           iat,
+		Exp:           exp,
+		TokenExp:      exp,
+	})
+	if err != nil {
+		return "", err
+	}
+	message := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	h := hmac.New(sha256.New, []byte(signer.Secret))
+	h.Write([]byte(message))
+	return message + "." + base64.RawURLEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// role is the role we sign for, host if we have a ZAK
+func (session *ZoomSession) role() int {
+	if session.zak != "" {
+		return RoleHost
+	}
+	return RoleAttendee
+}
+
+// IsHost reports whether zoom made us the host when we joined
+func (session *ZoomSession) IsHost() bool {
+	return session.CurrentJoinInfo().Role == RoleHost
+}
+
+// validateMeetingNumber checks the meeting number is something zoom could have given out.  it goes into signatures as a number so this also stops it being used to add other claims
+func validateMeetingNumber(meetingNumber string) error {
+	if len(meetingNumber) < 9 || len(meetingNumber) > 11 || strings.Trim(meetingNumber, "0123456789") != "" {
+		return fmt.Errorf("%w: %q", ErrInvalidMeetingNumber, meetingNumber)
+	}
+	return nil
+}
+
+// SignRequest is what HTTPSigner posts to the signing service.  it is the same as what zoom's sample auth endpoint takes
+type SignRequest struct {
+	MeetingNumber string `json:"meetingNumber"`
+	Role          int    `json:"role"`
+}
+
+// SignResponse is what the signing service sends back
+type SignResponse struct {
+	Signature string `json:"signature"`
+}
+
+// used by HTTPSigner when it isn't given a client.  Connect can't go any further without a signature, so don't let a stuck signing service hold it up forever
+var defaultSignerClient = &http.Client{
+	Timeout: 30 * time.Second,
+}
+
+// HTTPSigner gets signatures from a signing service (like the one in cmd/signer) so the SDK secret only has to be on that one machine
+type HTTPSigner struct {
+	URL    string
+	Token  string       // sent as a bearer token if not empty
+	Client *http.Client // a client with a 30 second timeout if nil
+}
+
+func (signer *HTTPSigner) Sign(ctx context.Context, meetingNumber string, role int) (string, error) {
+	requestBody, err := json.Marshal(SignRequest{
+		MeetingNumber: meetingNumber,
+		Role:          role,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, "POST", signer.URL, bytes.NewReader(requestBody))
+	if err != nil {
+		return "", err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if signer.Token != "" {
+		request.Header.Set("Authorization", "Bearer "+signer.Token)
+	}
+
+	client := signer.Client
+	if client == nil {
+		client = defaultSignerClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return "", err
+	}
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Signing service returned %s: %s", response.Status, bytes.TrimSpace(data))
+	}
+
+	var signResponse SignResponse
+	if err := json.Unmarshal(data, &signResponse); err != nil {
+		return "", err
+	}
+	if signResponse.Signature == "" {
+		return "", errors.New("Signing service returned no signature")
+	}
+	return signResponse.Signature, nil
+}
@@ -0,0 +1,178 @@
+package zoom_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+)
+
+// decodeSegment decodes one base64url part of a JWT as JSON into v
+func decodeSegment(t *testing.T, segment string, v interface{}) {
+	t.Helper()
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		t.Fatalf("Segment %q is not base64url: %v", segment, err)
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		t.Fatalf("Segment %s is not JSON: %v", data, err)
+	}
+}
+
+func TestHMACSignerSign(t *testing.T) {
+	tests := []struct {
+		name          string
+		meetingNumber string
+		role          int
+		lifetime      time.Duration
+		wantLifetime  time.Duration
+	}{
+		{"attendee", "1234567890", zoom.RoleAttendee, 0, zoom.DefaultSignatureLifetime},
+		{"host", "123456789", zoom.RoleHost, 0, zoom.DefaultSignatureLifetime},
+		{"shortest lifetime", "12345678901", zoom.RoleAttendee, zoom.MinSignatureLifetime, zoom.MinSignatureLifetime},
+		{"longest lifetime", "1234567890", zoom.RoleAttendee, zoom.MaxSignatureLifetime, zoom.MaxSignatureLifetime},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signer := &zoom.HMACSigner{Key: "testKey", Secret: "testSecret", Lifetime: test.lifetime}
+			before := time.Now().Unix()
+			signature, err := signer.Sign(context.Background(), test.meetingNumber, test.role)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			parts := strings.Split(signature, ".")
+			if len(parts) != 3 {
+				t.Fatalf("Signature has %d parts", len(parts))
+			}
+			var header map[string]interface{}
+			decodeSegment(t, parts[0], &header)
+			if header["alg"] != "HS256" || header["typ"] != "JWT" {
+				t.Errorf("Header is %v", header)
+			}
+
+			var claims map[string]interface{}
+			decodeSegment(t, parts[1], &claims)
+			if claims["appKey"] != "testKey" || claims["sdkKey"] != "testKey" {
+				t.Errorf("appKey is %v and sdkKey is %v", claims["appKey"], claims["sdkKey"])
+			}
+			// zoom wants the meeting number as a number, not a string
+			if mn, ok := claims["mn"].(json.Number); !ok || mn.String() != test.meetingNumber {
+				t.Errorf("mn is %#v", claims["mn"])
+			}
+			if role, ok := claims["role"].(json.Number); !ok || role.String() != strconv.Itoa(test.role) {
+				t.Errorf("role is %#v", claims["role"])
+			}
+			iat, _ := claims["iat"].(json.Number).Int64()
+			exp, _ := claims["exp"].(json.Number).Int64()
+			tokenExp, _ := claims["tokenExp"].(json.Number).Int64()
+			if iat > before || iat < before-60 {
+				t.Errorf("iat is %d, now is %d", iat, before)
+			}
+			if exp-iat != int64(test.wantLifetime/time.Second) || tokenExp != exp {
+				t.Errorf("iat %d, exp %d and tokenExp %d don't give a lifetime of %s", iat, exp, tokenExp, test.wantLifetime)
+			}
+
+			h := hmac.New(sha256.New, []byte("testSecret"))
+			h.Write([]byte(parts[0] + "." + parts[1]))
+			if parts[2] != base64.RawURLEncoding.EncodeToString(h.Sum(nil)) {
+				t.Errorf("Signature doesn't match the HMAC of the header and claims")
+			}
+		})
+	}
+}
+
+func TestHMACSignerRejects(t *testing.T) {
+	tests := []struct {
+		name          string
+		meetingNumber string
+		role          int
+		lifetime      time.Duration
+		wantErr       error
+	}{
+		{"short meeting number", "12345678", zoom.RoleAttendee, 0, zoom.ErrInvalidMeetingNumber},
+		{"long meeting number", "123456789012", zoom.RoleAttendee, 0, zoom.ErrInvalidMeetingNumber},
+		{"meeting number with letters", "12345678a", zoom.RoleAttendee, 0, zoom.ErrInvalidMeetingNumber},
+		// the meeting number goes into the claims unquoted so this would add a claim
+		{"meeting number injecting claims", `1,"role":1`, zoom.RoleAttendee, 0, zoom.ErrInvalidMeetingNumber},
+		{"unknown role", "1234567890", 2, 0, nil},
+		{"negative role", "1234567890", -1, 0, nil},
+		{"lifetime too short", "1234567890", zoom.RoleAttendee, zoom.MinSignatureLifetime - time.Second, nil},
+		{"lifetime too long", "1234567890", zoom.RoleAttendee, zoom.MaxSignatureLifetime + time.Second, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signer := &zoom.HMACSigner{Key: "testKey", Secret: "testSecret", Lifetime: test.lifetime}
+			signature, err := signer.Sign(context.Background(), test.meetingNumber, test.role)
+			if err == nil {
+				t.Fatalf("Signed %q", signature)
+			}
+			if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+				t.Errorf("Got %v, wanted %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewRejectsInvalidMeetingNumber(t *testing.T) {
+	if _, err := zoom.New("not a number", zoom.WithCredentials("testKey", "testSecret")); !errors.Is(err, zoom.ErrInvalidMeetingNumber) {
+		t.Errorf("New returned %v", err)
+	}
+}
+
+func TestHTTPSigner(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request zoom.SignRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, "Bad request", http.StatusBadRequest)
+			return
+		}
+		switch {
+		case r.Header.Get("Authorization") != "Bearer testToken":
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		case request.MeetingNumber == "1111111111":
+			json.NewEncoder(w).Encode(zoom.SignResponse{})
+		default:
+			json.NewEncoder(w).Encode(zoom.SignResponse{Signature: "signed-" + request.MeetingNumber + "-" + strconv.Itoa(request.Role)})
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	signer := &zoom.HTTPSigner{URL: server.URL, Token: "testToken"}
+	if signature, err := signer.Sign(ctx, "1234567890", zoom.RoleHost); err != nil || signature != "signed-1234567890-1" {
+		t.Errorf("Got %q, %v", signature, err)
+	}
+	if _, err := signer.Sign(ctx, "1111111111", zoom.RoleAttendee); err == nil {
+		t.Errorf("An empty signature was accepted")
+	}
+	wrongToken := &zoom.HTTPSigner{URL: server.URL, Token: "wrongToken"}
+	if _, err := wrongToken.Sign(ctx, "1234567890", zoom.RoleAttendee); err == nil || !strings.Contains(err.Error(), "Unauthorized") {
+		t.Errorf("Got %v with the wrong token", err)
+	}
+
+	// a service that never answers gives up with the context
+	release := make(chan struct{})
+	stuck := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer stuck.Close()
+	defer close(release)
+	shortCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := (&zoom.HTTPSigner{URL: stuck.URL}).Sign(shortCtx, "1234567890", zoom.RoleAttendee); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Got %v from a stuck service", err)
+	}
+}
@@ -0,0 +1,125 @@
+package zoom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionSnapshot is what Snapshot saves: everything needed for a new process to rejoin the meeting as the same participant
+type SessionSnapshot struct {
+	MeetingNumber  string                 `json:"meetingNumber"`
+	HardwareID     uuid.UUID              `json:"hardwareID"`
+	JoinInfo       JoinConferenceResponse `json:"joinInfo"`
+	MeetingOpt     string                 `json:"meetingOpt,omitempty"`
+	MainMeetingOpt string                 `json:"mainMeetingOpt,omitempty"`
+	BreakoutBID    string                 `json:"breakoutBID,omitempty"`
+	Cookies        []*http.Cookie         `json:"cookies,omitempty"` // only names and values, for the meeting info endpoint.  read from snapshots made before SavedCookies
+	SavedCookies   []SavedCookie          `json:"savedCookies,omitempty"`
+	Roster         []RosterAddItem        `json:"roster,omitempty"`
+	Time           time.Time              `json:"time"`
+}
+
+// Snapshot writes the session's join state as JSON to w.  Give it to Restore on a session for the same meeting after a restart and Connect will rejoin as the same participant instead of a new one.
+// It contains tokens, so keep it somewhere private.
+func (session *ZoomSession) Snapshot(w io.Writer) error {
+	session.mu.Lock()
+	snapshot := SessionSnapshot{
+		MeetingNumber:  session.MeetingNumber,
+		HardwareID:     session.HardwareID,
+		JoinInfo:       session.JoinInfo,
+		MeetingOpt:     session.meetingOpt,
+		MainMeetingOpt: session.mainMeetingOpt,
+		BreakoutBID:    session.breakoutBID,
+		Time:           time.Now(),
+	}
+	session.mu.Unlock()
+	snapshot.Roster = session.Roster()
+	snapshot.SavedCookies = session.cookieJar.saved()
+
+	if snapshot.JoinInfo.ZoomID == "" {
+		return ErrNotConnected
+	}
+	return json.NewEncoder(w).Encode(&snapshot)
+}
+
+// SnapshotFile is Snapshot to a file.  The file is replaced in one go so a crash while saving doesn't leave a broken snapshot behind.
+func (session *ZoomSession) SnapshotFile(path string) error {
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name()) // fails harmlessly after the rename
+
+	if err := session.Snapshot(file); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+// Restore loads a snapshot written by Snapshot.  Call it before Connect (or WaitForMeetingStart), which will then rejoin as the participant in the snapshot.
+func (session *ZoomSession) Restore(r io.Reader) error {
+	var snapshot SessionSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return fmt.Errorf("Failed to read snapshot: %w", err)
+	}
+	if snapshot.MeetingNumber != session.MeetingNumber {
+		return fmt.Errorf("Snapshot is for meeting %s, not %s", snapshot.MeetingNumber, session.MeetingNumber)
+	}
+
+	if len(snapshot.SavedCookies) > 0 {
+		session.cookieJar.restore(snapshot.SavedCookies)
+	} else {
+		session.SetCookies(snapshot.Cookies)
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	// zoom expects the rejoin to come from the same device
+	session.HardwareID = snapshot.HardwareID
+	session.JoinInfo = snapshot.JoinInfo
+	session.meetingOpt = snapshot.MeetingOpt
+	session.mainMeetingOpt = snapshot.MainMeetingOpt
+	session.breakoutBID = snapshot.BreakoutBID
+	session.roster = make(map[int]RosterAddItem, len(snapshot.Roster))
+	for _, person := range snapshot.Roster {
+		session.roster[person.ID] = person
+	}
+	session.resume = true
+	return nil
+}
+
+// RestoreFile is Restore from a file
+func (session *ZoomSession) RestoreFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return session.Restore(file)
+}
+
+// resuming reports whether Connect should rejoin using restored state
+func (session *ZoomSession) resuming() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.resume
+}
+
+// resumed is called once we have rejoined, so later Connects join normally
+func (session *ZoomSession) resumed() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.resume = false
+}
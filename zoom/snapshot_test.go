@@ -0,0 +1,79 @@
+package zoom_test
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	server.WaitingRoom = true
+	session := newTestSession(t, server)
+
+	// get through the waiting room so there is an opt to save
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if err := session.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- session.Run(context.Background(), nil)
+	}()
+	waitForState(t, session, zoom.StateInWaitingRoom)
+	server.Send(zoom.WS_CONF_HOLD_CHANGE_INDICATION, zoom.ConferenceHoldChangeIndication{BHold: false})
+	waitForState(t, session, zoom.StateAdmitted)
+	server.DropConnections()
+	waitForState(t, session, zoom.StateInMeeting)
+
+	var snapshot bytes.Buffer
+	if err := session.Snapshot(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	joinInfo := session.CurrentJoinInfo()
+	leave(t, session, runErr)
+
+	restored := newTestSession(t, server)
+	if err := restored.Restore(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	// the meeting info endpoint and the RWG set their own cookies, which have to go back to the same places
+	infoURL, _ := url.Parse(server.Endpoints().Info)
+	rwgURL, _ := url.Parse(strings.Replace(strings.Replace(server.URL(), "ws://", "http://", 1), "127.0.0.1", "localhost", 1))
+	for _, c := range []struct {
+		url    *url.URL
+		cookie string
+	}{
+		{infoURL, "_zm_ssid"},
+		{rwgURL, "_zm_rwg"},
+	} {
+		found := false
+		for _, cookie := range restored.CookieJar().Cookies(c.url) {
+			found = found || cookie.Name == c.cookie
+		}
+		if !found {
+			t.Errorf("%s wasn't restored for %s", c.cookie, c.url.Host)
+		}
+	}
+
+	restoredRunErr := startSession(t, restored, nil)
+	join := server.Joins()[len(server.Joins())-1]
+	if zoomID := join.Get("zoomid"); zoomID != joinInfo.ZoomID {
+		t.Errorf("Rejoined with zoomid %q, wanted %q", zoomID, joinInfo.ZoomID)
+	}
+	if participantID := join.Get("participantID"); participantID != strconv.Itoa(joinInfo.ParticipantID) {
+		t.Errorf("Rejoined with participantID %q, wanted %d", participantID, joinInfo.ParticipantID)
+	}
+	if opt := join.Get("opt"); opt != zoomtest.MeetingOpt {
+		t.Errorf("Rejoined with opt %q", opt)
+	}
+	leave(t, restored, restoredRunErr)
+}
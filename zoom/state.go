@@ -0,0 +1,100 @@
+package zoom
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// State is where the session is in the meeting lifecycle, see ZoomSession.State
+type State int
+
+const (
+	StateDisconnected   State = iota // not connected, or Run has returned
+	StateConnecting                  // fetching meeting info and opening the websocket
+	StateInWaitingRoom               // connected but held in the waiting room
+	StateAdmitted                    // let out of the waiting room, moving to the main meeting
+	StateInMeeting                   // in the main meeting
+	StateInBreakout                  // in a breakout room
+	StateEnded                       // the meeting was ended by the host
+	StateWaitingForHost              // polling for the host to start the meeting, see WaitForMeetingStart
+)
+
+func (state State) String() string {
+	switch state {
+	case StateDisconnected:
+		return "Disconnected"
+	case StateConnecting:
+		return "Connecting"
+	case StateInWaitingRoom:
+		return "InWaitingRoom"
+	case StateAdmitted:
+		return "Admitted"
+	case StateInMeeting:
+		return "InMeeting"
+	case StateInBreakout:
+		return "InBreakout"
+	case StateEnded:
+		return "Ended"
+	case StateWaitingForHost:
+		return "WaitingForHost"
+	}
+	return fmt.Sprintf("State(%d)", int(state))
+}
+
+// StateChange is passed to the onMessage function (and OnStateChange handlers) whenever the session state changes.  like Disconnected it is not sent by zoom
+type StateChange struct {
+	From State
+	To   State
+}
+
+// State returns the current lifecycle state of the session
+func (session *ZoomSession) State() State {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.state
+}
+
+// setState moves the session to state and notifies handlers if that is a change
+func (session *ZoomSession) setState(onMessageFunction onMessage, state State) {
+	session.mu.Lock()
+	from := session.state
+	session.state = state
+	session.mu.Unlock()
+
+	if from == state {
+		return
+	}
+	session.logger.Info("State changed", "from", from, "to", state)
+	session.dispatch(onMessageFunction, &StateChange{From: from, To: state})
+}
+
+// OnStateChange registers a handler for every state change.  Call the returned function to remove it.
+func (session *ZoomSession) OnStateChange(fn func(session *ZoomSession, change *StateChange) error) func() {
+	return session.handlers.add(reflect.TypeOf(StateChange{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*StateChange))
+	})
+}
+
+// OnWaitingRoom registers a handler for being put in the waiting room.  Call the returned function to remove it.
+func (session *ZoomSession) OnWaitingRoom(fn func(session *ZoomSession) error) func() {
+	return session.onStateEntered(StateInWaitingRoom, fn)
+}
+
+// OnAdmitted registers a handler for being let out of the waiting room.  Call the returned function to remove it.
+func (session *ZoomSession) OnAdmitted(fn func(session *ZoomSession) error) func() {
+	return session.onStateEntered(StateAdmitted, fn)
+}
+
+// OnMeetingEnded registers a handler for the host ending the meeting.  Call the returned function to remove it.
+func (session *ZoomSession) OnMeetingEnded(fn func(session *ZoomSession) error) func() {
+	return session.onStateEntered(StateEnded, fn)
+}
+
+func (session *ZoomSession) onStateEntered(state State, fn func(session *ZoomSession) error) func() {
+	return session.OnStateChange(func(session *ZoomSession, change *StateChange) error {
+		if change.To != state {
+			return nil
+		}
+		return fn(session)
+	})
+}
@@ -0,0 +1,129 @@
+package zoom_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+func TestWaitingRoomAdmission(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	var waiting, admitted int32
+	session.OnWaitingRoom(func(session *zoom.ZoomSession) error {
+		atomic.AddInt32(&waiting, 1)
+		return nil
+	})
+	session.OnAdmitted(func(session *zoom.ZoomSession) error {
+		atomic.AddInt32(&admitted, 1)
+		return nil
+	})
+	runErr := startSession(t, session, nil)
+
+	server.Send(zoom.WS_CONF_HOLD_CHANGE_INDICATION, zoom.ConferenceHoldChangeIndication{BHold: true})
+	server.Send(zoom.WS_CONF_OPTION_INDICATION, zoom.ConferenceOptionIndication{Opt: "fakeMeetingOpt"})
+	waitForState(t, session, zoom.StateInWaitingRoom)
+
+	// zoom lets us in and then drops the waiting room connection
+	server.Send(zoom.WS_CONF_HOLD_CHANGE_INDICATION, zoom.ConferenceHoldChangeIndication{BHold: false})
+	waitForState(t, session, zoom.StateAdmitted)
+	server.DropConnections()
+	waitForConnection(t, server, 2)
+	waitForState(t, session, zoom.StateInMeeting)
+
+	if opt := server.Joins()[1].Get("opt"); opt != "fakeMeetingOpt" {
+		t.Errorf("Joined the main meeting with opt %q", opt)
+	}
+
+	// Run waits for the handlers to finish so the counts are final after this
+	leave(t, session, runErr)
+	if n := atomic.LoadInt32(&waiting); n != 1 {
+		t.Errorf("OnWaitingRoom called %d times", n)
+	}
+	if n := atomic.LoadInt32(&admitted); n != 1 {
+		t.Errorf("OnAdmitted called %d times", n)
+	}
+}
+
+func TestDropInWaitingRoomIsNotAdmission(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	var admitted, reconnected int32
+	session.OnAdmitted(func(session *zoom.ZoomSession) error {
+		atomic.AddInt32(&admitted, 1)
+		return nil
+	})
+	session.OnReconnected(func(session *zoom.ZoomSession, message *zoom.Reconnected) error {
+		atomic.AddInt32(&reconnected, 1)
+		return nil
+	})
+	runErr := startSession(t, session, nil)
+
+	server.Send(zoom.WS_CONF_HOLD_CHANGE_INDICATION, zoom.ConferenceHoldChangeIndication{BHold: true})
+	waitForState(t, session, zoom.StateInWaitingRoom)
+	// the network goes while we are still held
+	server.DropConnections()
+	waitForConnection(t, server, 2)
+	waitForState(t, session, zoom.StateInMeeting)
+
+	leave(t, session, runErr)
+	if n := atomic.LoadInt32(&admitted); n != 0 {
+		t.Errorf("OnAdmitted called %d times", n)
+	}
+	if n := atomic.LoadInt32(&reconnected); n != 1 {
+		t.Errorf("OnReconnected called %d times", n)
+	}
+}
+
+func TestJoinIntoWaitingRoom(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	server.WaitingRoom = true
+	session := newTestSession(t, server)
+
+	var mu sync.Mutex
+	var changes []zoom.State
+	session.OnStateChange(func(session *zoom.ZoomSession, change *zoom.StateChange) error {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, change.To)
+		return nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if err := session.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- session.Run(context.Background(), nil)
+	}()
+	waitForState(t, session, zoom.StateInWaitingRoom)
+
+	server.Send(zoom.WS_CONF_HOLD_CHANGE_INDICATION, zoom.ConferenceHoldChangeIndication{BHold: false})
+	waitForState(t, session, zoom.StateAdmitted)
+	server.DropConnections()
+	waitForConnection(t, server, 2)
+	waitForState(t, session, zoom.StateInMeeting)
+	if opt := server.Joins()[1].Get("opt"); opt != zoomtest.MeetingOpt {
+		t.Errorf("Joined the main meeting with opt %q", opt)
+	}
+
+	leave(t, session, runErr)
+	mu.Lock()
+	defer mu.Unlock()
+	// nothing should think we are in the meeting while we are held
+	want := []zoom.State{zoom.StateConnecting, zoom.StateInWaitingRoom, zoom.StateAdmitted, zoom.StateConnecting, zoom.StateInMeeting, zoom.StateDisconnected}
+	if fmt.Sprint(changes) != fmt.Sprint(want) {
+		t.Errorf("State went through %v, wanted %v", changes, want)
+	}
+}
@@ -0,0 +1,69 @@
+package zoom
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// polling backs off up to this (or pollInterval if that is longer) so a bot left waiting for hours isn't hammering zoom
+const maxMeetingStartPollInterval = 1 * time.Minute
+
+// WaitForMeetingStart is like Connect but if the host hasn't started the meeting yet it keeps polling the meeting info (starting at pollInterval and backing off) until RWC servers show up, then connects.  Any other error from the meeting info request is returned straight away.
+func (session *ZoomSession) WaitForMeetingStart(ctx context.Context, pollInterval time.Duration) error {
+	if pollInterval <= 0 {
+		return errors.New("Poll interval must be positive")
+	}
+	session.resetLeft()
+	if err := session.waitForMeetingStart(ctx, pollInterval); err != nil {
+		session.setState(nil, StateDisconnected)
+		return err
+	}
+	return nil
+}
+
+func (session *ZoomSession) waitForMeetingStart(ctx context.Context, pollInterval time.Duration) error {
+	maxDelay := maxMeetingStartPollInterval
+	if pollInterval > maxDelay {
+		maxDelay = pollInterval
+	}
+	// same backoff as reconnecting, just with different numbers
+	policy := &ReconnectPolicy{
+		InitialDelay: pollInterval,
+		MaxDelay:     maxDelay,
+		Multiplier:   1.5,
+	}
+
+	// polling is part of WaitingForHost, we only go back to Connecting once the meeting has started
+	session.setState(nil, StateConnecting)
+	for attempt := 1; ; attempt++ {
+		meetingInfo, err := session.getMeetingInfoData(ctx)
+		if err == nil && len(meetingInfo.Result.EncryptedRWC) > 0 {
+			session.setState(nil, StateConnecting)
+			websocketUrl, err := session.getWebsocketUrl(ctx, meetingInfo, session.resuming())
+			if err != nil {
+				return err
+			}
+			if err := session.dial(ctx, websocketUrl, ""); err != nil {
+				return err
+			}
+			session.resumed()
+			return nil
+		}
+		// zoom either says the meeting hasn't started or gives us info without any servers to connect to
+		if err != nil && !errors.Is(err, ErrMeetingNotStarted) {
+			return err
+		}
+
+		delay := policy.delay(attempt)
+		session.logger.Info("Waiting for host to start meeting", "attempt", attempt, "delay", delay)
+		session.setState(nil, StateWaitingForHost)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package zoom_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+func TestWaitForMeetingStart(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	var mu sync.Mutex
+	var changes []zoom.State
+	session.OnStateChange(func(session *zoom.ZoomSession, change *zoom.StateChange) error {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, change.To)
+		return nil
+	})
+
+	server.SetInfoError(3008, "Meeting has not started")
+	connected := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+		defer cancel()
+		connected <- session.WaitForMeetingStart(ctx, 10*time.Millisecond)
+	}()
+
+	// let it poll a few times before the host turns up
+	time.Sleep(100 * time.Millisecond)
+	server.SetInfoError(0, "")
+	select {
+	case err := <-connected:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("WaitForMeetingStart did not return")
+	}
+	waitForConnection(t, server, 1)
+
+	// no flipping back and forth while polling
+	mu.Lock()
+	defer mu.Unlock()
+	want := []zoom.State{zoom.StateConnecting, zoom.StateWaitingForHost, zoom.StateConnecting}
+	if len(changes) != len(want) {
+		t.Fatalf("State changes were %v, wanted %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("State changes were %v, wanted %v", changes, want)
+		}
+	}
+}
+
+func TestWaitForMeetingStartRejectsBadInterval(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if err := session.WaitForMeetingStart(ctx, interval); err == nil {
+			t.Errorf("WaitForMeetingStart with a poll interval of %s succeeded", interval)
+		}
+	}
+	if state := session.State(); state != zoom.StateDisconnected {
+		t.Errorf("State is %s", state)
+	}
+}
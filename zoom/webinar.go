@@ -0,0 +1,92 @@
+package zoom
+
+import (
+	"reflect"
+)
+
+// roles in WS_CONF_ROLE_CHANGE_REQ/RES, from reading the web client (untested)
+const (
+	WebinarRolePanelist = 1
+	WebinarRoleAttendee = 2
+)
+
+// WebinarRoleChange is passed to the onMessage function (and OnWebinarRoleChange handlers) when we are promoted to panelist or demoted to attendee.  like StateChange it is not sent by zoom
+type WebinarRoleChange struct {
+	From int // WebinarRolePanelist or WebinarRoleAttendee
+	To   int
+}
+
+// WebinarTalkChange is passed to the onMessage function (and OnWebinarTalkChange handlers) when the host lets us talk as an attendee or stops us.  like StateChange it is not sent by zoom
+type WebinarTalkChange struct {
+	Allowed bool
+}
+
+// IsWebinar reports whether the meeting we connected to is a webinar
+func (session *ZoomSession) IsWebinar() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.isWebinar
+}
+
+// WebinarRole returns WebinarRolePanelist or WebinarRoleAttendee, or 0 if we aren't in a webinar (or are the host)
+func (session *ZoomSession) WebinarRole() int {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.webinarRole
+}
+
+// OnWebinarRoleChange registers a handler for being promoted to panelist or demoted to attendee.  Call the returned function to remove it.
+func (session *ZoomSession) OnWebinarRoleChange(fn func(session *ZoomSession, change *WebinarRoleChange) error) func() {
+	return session.handlers.add(reflect.TypeOf(WebinarRoleChange{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*WebinarRoleChange))
+	})
+}
+
+// OnWebinarTalkChange registers a handler for the host allowing or stopping us talking as an attendee.  Call the returned function to remove it.
+func (session *ZoomSession) OnWebinarTalkChange(fn func(session *ZoomSession, change *WebinarTalkChange) error) func() {
+	return session.handlers.add(reflect.TypeOf(WebinarTalkChange{}), func(session *ZoomSession, message Message) error {
+		return fn(session, message.(*WebinarTalkChange))
+	})
+}
+
+// setWebinarRole records our role and notifies handlers if that is a change
+func (session *ZoomSession) setWebinarRole(onMessageFunction onMessage, role int) {
+	session.mu.Lock()
+	from := session.webinarRole
+	session.webinarRole = role
+	session.mu.Unlock()
+
+	if from == role {
+		return
+	}
+	session.logger.Info("Webinar role changed", "from", from, "to", role)
+	// the first role we get is where we start, not a change
+	if from != 0 {
+		session.dispatch(onMessageFunction, &WebinarRoleChange{From: from, To: role})
+	}
+}
+
+// handleWebinarMessage is called by the reader to keep track of our webinar role.  zoom leaves attendees out of the roster and sends them WS_WEBINAR_VIEW_ONLY_TELEPHONY_INDICATION instead, so we are a panelist while we are in it
+func (session *ZoomSession) handleWebinarMessage(onMessageFunction onMessage, message Message) {
+	joinInfo := session.CurrentJoinInfo()
+	if !session.IsWebinar() || joinInfo.Role == RoleHost {
+		return
+	}
+	switch m := message.(type) {
+	case *WebinarViewOnlyTelephonyIndication:
+		session.setWebinarRole(onMessageFunction, WebinarRoleAttendee)
+	case *ConferenceRosterIndication:
+		for _, person := range m.Add {
+			if person.ID == joinInfo.UserID {
+				session.setWebinarRole(onMessageFunction, WebinarRolePanelist)
+			}
+		}
+		for _, person := range m.Remove {
+			if person.ID == joinInfo.UserID && session.WebinarRole() == WebinarRolePanelist {
+				session.setWebinarRole(onMessageFunction, WebinarRoleAttendee)
+			}
+		}
+	case *AudioAllowTalkIndication:
+		session.dispatch(onMessageFunction, &WebinarTalkChange{Allowed: m.BAllowTalk})
+	}
+}
@@ -0,0 +1,137 @@
+package zoom_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+func TestWebinarAttendeeNeedsEmail(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	server.Webinar = true
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if err := newTestSession(t, server).Connect(ctx); !errors.Is(err, zoom.ErrEmailRequired) {
+		t.Fatalf("Connect without an email returned %v", err)
+	}
+	if len(server.Joins()) != 0 {
+		t.Errorf("Connected to the webinar without an email")
+	}
+
+	session := newTestSession(t, server, zoom.WithEmail("bot@example.com"))
+	runErr := startSession(t, session, nil)
+	if !session.IsWebinar() {
+		t.Errorf("IsWebinar is false")
+	}
+	if email := server.Joins()[0].Get("email"); email != base64.StdEncoding.EncodeToString([]byte("bot@example.com")) {
+		t.Errorf("Joined with email %q", email)
+	}
+	leave(t, session, runErr)
+}
+
+func TestPromoteToPanelist(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	server.Webinar = true
+	server.Handle(zoom.WS_CONF_ROLE_CHANGE_REQ, func(server *zoomtest.Server, message *zoom.GenericZoomMessage) {
+		var request zoom.ConferenceRoleChangeRequest
+		if err := json.Unmarshal(message.Body, &request); err != nil {
+			t.Error(err)
+			return
+		}
+		server.Send(zoom.WS_CONF_ROLE_CHANGE_RES, zoom.ConferenceRoleChangeResponse{ID: request.ID, Role: request.Role})
+	})
+	session := newTestSession(t, server, zoom.WithZAK("fakeZAK"))
+	runErr := startSession(t, session, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	response, err := session.PromoteToPanelist(ctx, 16778240)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.ID != 16778240 || response.Role != zoom.WebinarRolePanelist {
+		t.Errorf("Got response %+v", response)
+	}
+
+	leave(t, session, runErr)
+}
+
+func TestWebinarRoleAndTalkChanges(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	server.Webinar = true
+	session := newTestSession(t, server, zoom.WithEmail("bot@example.com"))
+
+	roleChanges := make(chan *zoom.WebinarRoleChange, 2)
+	session.OnWebinarRoleChange(func(session *zoom.ZoomSession, change *zoom.WebinarRoleChange) error {
+		roleChanges <- change
+		return nil
+	})
+	talkChanges := make(chan *zoom.WebinarTalkChange, 1)
+	session.OnWebinarTalkChange(func(session *zoom.ZoomSession, change *zoom.WebinarTalkChange) error {
+		talkChanges <- change
+		return nil
+	})
+	runErr := startSession(t, session, nil)
+	waitForWebinarRole(t, session, zoom.WebinarRoleAttendee)
+
+	expectRoleChange := func(from, to int) {
+		t.Helper()
+		select {
+		case change := <-roleChanges:
+			if change.From != from || change.To != to {
+				t.Errorf("Got role change %+v, wanted %d to %d", change, from, to)
+			}
+		case <-time.After(testTimeout):
+			t.Fatalf("No role change from %d to %d", from, to)
+		}
+	}
+	// promoted panelists show up in the roster
+	userID := server.JoinResponse.UserID
+	server.Send(zoom.WS_CONF_ROSTER_INDICATION, zoom.ConferenceRosterIndication{
+		Add: []zoom.RosterAddItem{{ID: userID}},
+	})
+	expectRoleChange(zoom.WebinarRoleAttendee, zoom.WebinarRolePanelist)
+	if role := session.WebinarRole(); role != zoom.WebinarRolePanelist {
+		t.Errorf("WebinarRole after promotion is %d", role)
+	}
+	server.Send(zoom.WS_CONF_ROSTER_INDICATION, zoom.ConferenceRosterIndication{
+		Remove: []struct {
+			ID          int `json:"id,omitempty"`
+			NUSerStatus int `json:"nUserStatus,omitempty"`
+		}{{ID: userID}},
+	})
+	expectRoleChange(zoom.WebinarRolePanelist, zoom.WebinarRoleAttendee)
+
+	server.Send(zoom.WS_AUDIO_ALLOW_TALK_INDICATION, zoom.AudioAllowTalkIndication{BAllowTalk: true})
+	select {
+	case change := <-talkChanges:
+		if !change.Allowed {
+			t.Errorf("Talk change says we are not allowed to talk")
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("No talk change")
+	}
+
+	leave(t, session, runErr)
+}
+
+func waitForWebinarRole(t *testing.T, session *zoom.ZoomSession, role int) {
+	t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for session.WebinarRole() != role {
+		if time.Now().After(deadline) {
+			t.Fatalf("WebinarRole is %d, wanted %d", session.WebinarRole(), role)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -1,29 +1,34 @@
 package zoom
 
 import (
-	"crypto/tls"
+	"context"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
-	"os/signal"
-	"time"
-
-	"github.com/gorilla/websocket"
-
-	// "github.com/google/uuid"
-	"encoding/json"
-	"log"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
 )
 
-func getRwgPingServer(meetingInfo *MeetingInfo) *RwgInfo {
+// rwgHost returns the host to use for an RWG server zoom told us about, taking Endpoints.RwgHost into account
+func (session *ZoomSession) rwgHost(host string) string {
+	if session.Endpoints.RwgHost != "" {
+		return session.Endpoints.RwgHost
+	}
+	return host
+}
+
+func (session *ZoomSession) getRwgPingServer(meetingInfo *MeetingInfo) *RwgInfo {
 	var rwgPingInfo RwgInfo
 
 	for key, value := range meetingInfo.Result.EncryptedRWC {
-		rwgPingInfo.Rwg = key
+		rwgPingInfo.Rwg = session.rwgHost(key)
 		rwgPingInfo.RwcAuth = value
 		break
 	}
@@ -32,8 +37,12 @@ func getRwgPingServer(meetingInfo *MeetingInfo) *RwgInfo {
 }
 
 func (session *ZoomSession) GetWebsocketUrl(meetingInfo *MeetingInfo, wasInWaitingRoom bool) (string, error) {
-	pingRwcServer := getRwgPingServer(meetingInfo)
-	rwgInfo, err := session.getRwgPingData(meetingInfo, pingRwcServer)
+	return session.getWebsocketUrl(context.Background(), meetingInfo, wasInWaitingRoom)
+}
+
+func (session *ZoomSession) getWebsocketUrl(ctx context.Context, meetingInfo *MeetingInfo, rejoin bool) (string, error) {
+	pingRwcServer := session.getRwgPingServer(meetingInfo)
+	rwgInfo, err := session.getRwgPingData(ctx, meetingInfo, pingRwcServer)
 	if err != nil {
 		return "", err
 	}
@@ -45,28 +54,32 @@ func (session *ZoomSession) GetWebsocketUrl(meetingInfo *MeetingInfo, wasInWaiti
 	// query string for websocket url
 	values := url.Values{}
 	values.Set("dn2", base64.StdEncoding.EncodeToString([]byte(meetingInfo.Result.UserName)))
-	values.Set("browser", userAgentShorthand)
+	values.Set("browser", session.userAgent.Shorthand)
 	values.Set("trackAuth", meetingInfo.Result.TrackAuth)
 	values.Set("mid", meetingInfo.Result.Mid)
 	values.Set("tid", meetingInfo.Result.Tid)
-	values.Set("lang", "en")
+	values.Set("lang", strings.SplitN(session.language, "-", 2)[0])
 	values.Set("ts", strconv.FormatInt(meetingInfo.Result.Ts, 10))
 	values.Set("auth", meetingInfo.Result.Auth)
 	values.Set("sign", meetingInfo.Result.Sign)
 	// values.Set("ZM-CID", uuid.New().String()) // random uuid
 	values.Set("ZM-CID", session.HardwareID.String()) // this is a hardware id.  you shouldnt have it change a bunch of times per ip or you will look highly suspicious
 	values.Set("_ZM_MTG_TRACK_ID", "")
-	values.Set("jscv", "2.12.0")
+	values.Set("jscv", session.sdkVersion)
 	// values.Set("jscv", "1.8.5")
 	values.Set("fromNginx", "undefined")
 	values.Set("mpwd", meetingInfo.Result.Password)
-	values.Set("zak", "")
+	values.Set("zak", session.zak)
 	values.Set("signType", "sdk")
 	values.Set("sign", meetingInfo.Result.Sign)
 	values.Set("rwcAuth", rwgInfo.RwcAuth)
 	values.Set("as_type", "1")
-	values.Set("email", "0")
-	values.Set("tk", "")
+	if session.email != "" {
+		values.Set("email", base64.StdEncoding.EncodeToString([]byte(session.email)))
+	} else {
+		values.Set("email", "0")
+	}
+	values.Set("tk", session.registrantToken)
 	values.Set("cfs", "0")
 	values.Set("clientCaps", "595")
 
@@ -124,149 +137,498 @@ func (session *ZoomSession) GetWebsocketUrl(meetingInfo *MeetingInfo, wasInWaiti
 	// unknown
 
 	// "opt" is a parameter to specify a meeting within a meeting, for instance breakout rooms or the main meeting in a meeting with waiting room enabled
-	if wasInWaitingRoom {
-		values.Set("opt", session.meetingOpt)
-		values.Set("zoomid", session.JoinInfo.ZoomID)
-		values.Set("participantID", strconv.Itoa(session.JoinInfo.ParticipantID))
+	// zoomid and participantID make zoom treat us as the same participant when we come back after the waiting room or a dropped connection
+	if rejoin {
+		session.mu.Lock()
+		meetingOpt, joinInfo := session.meetingOpt, session.JoinInfo
+		session.mu.Unlock()
+		if meetingOpt != "" {
+			values.Set("opt", meetingOpt)
+		}
+		values.Set("zoomid", joinInfo.ZoomID)
+		values.Set("participantID", strconv.Itoa(joinInfo.ParticipantID))
 	}
 
-	return fmt.Sprintf("wss://%s/wc/api/%s?%s", rwgInfo.Rwg, meetingInfo.Result.MeetingNumber, values.Encode()), nil
+	return fmt.Sprintf("%s://%s/wc/api/%s?%s", session.Endpoints.WebsocketScheme, session.rwgHost(rwgInfo.Rwg), meetingInfo.Result.MeetingNumber, values.Encode()), nil
 }
 
 type onMessage func(session *ZoomSession, message Message) error
 
-func (session *ZoomSession) MakeWebsocketConnection(websocketUrl string, cookieString string, onMessageFunction onMessage) error {
+// returned by Run (or passed to the Disconnected event when reconnecting) when the server has not sent anything for longer than the stale timeout, see WithKeepalive
+var ErrConnectionStale = errors.New("Connection stale: nothing received from server")
+
+// returned by Run when we are held in the waiting room for longer than the waiting room timeout, see WithWaitingRoomTimeout
+var ErrWaitingRoomTimeout = errors.New("Timed out in waiting room")
+
+// returned by the reader when the waiting room connection is dropped so that we can rejoin the main meeting using the stored meetingOpt
+var errLeftWaitingRoom = errors.New("left waiting room")
+
+// how long to wait for the server to acknowledge our close frame before tearing down the connection ourselves
+const closeTimeout = 5 * time.Second
+
+// Connect fetches the meeting info, finds an RWG server and dials the meeting websocket.  Call Run afterwards to start processing messages.
+func (session *ZoomSession) Connect(ctx context.Context) error {
+	session.resetLeft()
+	session.setState(nil, StateConnecting)
+	if err := session.connect(ctx, session.resuming()); err != nil {
+		session.setState(nil, StateDisconnected)
+		return err
+	}
+	session.resumed()
+	return nil
+}
+
+func (session *ZoomSession) connect(ctx context.Context, rejoin bool) error {
+	// get the rwc token and other info needed to construct the websocket url for the meeting
+	meetingInfo, err := session.getMeetingInfoData(ctx)
+	if err != nil {
+		return err
+	}
+	websocketUrl, err := session.getWebsocketUrl(ctx, meetingInfo, rejoin)
+	if err != nil {
+		return err
+	}
+	return session.dial(ctx, websocketUrl, "")
+}
+
+// ConnectWebsocket dials websocketUrl directly, skipping the meeting info lookup done by Connect.  cookieString (which may be empty) is formatted like a Cookie header, its cookies are added to the session's jar for the websocket's host and sent along with the rest.  Useful when you already have a websocket url (see GetWebsocketUrl) or for testing against zoomtest.Server.
+func (session *ZoomSession) ConnectWebsocket(ctx context.Context, websocketUrl string, cookieString string) error {
+	session.resetLeft()
+	session.setState(nil, StateConnecting)
+	if err := session.dial(ctx, websocketUrl, cookieString); err != nil {
+		session.setState(nil, StateDisconnected)
+		return err
+	}
+	return nil
+}
+
+func (session *ZoomSession) dial(ctx context.Context, websocketUrl string, cookieString string) error {
 	dialer := websocket.Dialer{
-		// TODO: REMOVE -- DEV ONLY FOR CHARLES PROXY
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: true,
-		},
+		TLSClientConfig:  session.tlsConfig,
+		HandshakeTimeout: session.handshakeTimeout,
+		Jar:              session.cookieJar,
 	}
 	if session.ProxyURL != nil {
 		dialer.Proxy = http.ProxyURL(session.ProxyURL)
 	}
 
-	connection, _, err := dialer.Dial(websocketUrl, http.Header{
-		"Accept-Language": []string{"en-US,en;q=0.9"},
+	session.logger.Debug("Connecting to websocket", "url", session.redactURL(websocketUrl))
+	headers := http.Header{
+		"Accept-Language": []string{acceptLanguage(session.language)},
 		"Cache-Control":   []string{"no-cache"},
 		"Origin":          []string{"http://localhost:9999"},
 		"Pragma":          []string{"no-cache"},
-		"User-Agent":      []string{userAgent},
-		"Cookie":          []string{cookieString},
-	})
+		"User-Agent":      []string{session.userAgent.Header},
+	}
+	// gorilla replaces any Cookie header with the jar's cookies, so extra ones have to go through the jar
+	if cookieString != "" {
+		if err := session.setWebsocketCookies(websocketUrl, cookieString); err != nil {
+			return err
+		}
+	}
+	connection, _, err := dialer.DialContext(ctx, websocketUrl, headers)
 	if err != nil {
 		return err
 	}
-	defer connection.Close()
 
+	session.mu.Lock()
 	session.websocketConnection = connection
+	session.mu.Unlock()
+	return nil
+}
 
-	wasInWaitingRoom := false
-	done := make(chan struct{})
-	go func() {
-		defer close(done)
-		for {
-			var message GenericZoomMessage
+// Run processes messages on the connection opened by Connect until ctx is cancelled, Leave is called, the meeting ends or the connection fails.
+// onMessageFunction (which may be nil) is called for every message received, followed by any handlers added with the On* methods.
+// They are called one at a time on a goroutine of their own rather than the one reading from the connection, so they can use SendAndWait (and the requests built on it) while the reader keeps going.  Run waits for them to finish before returning.
+// If session.Reconnect is set, dropped connections are reestablished according to that policy instead of being returned.
+// When ctx is cancelled a leave request is sent, the websocket is closed and ctx.Err() is returned.  After Leave it returns nil.
+func (session *ZoomSession) Run(ctx context.Context, onMessageFunction onMessage) error {
+	if session.currentConnection() == nil {
+		return errors.New("Run called before Connect")
+	}
+
+	// Leave cancels this context to stop us
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	runDone := make(chan struct{})
+	dispatcher := newDispatcher()
+	session.mu.Lock()
+	session.cancelRun = cancel
+	session.runDone = runDone
+	session.dispatcher = dispatcher
+	session.mu.Unlock()
+	defer func() {
+		session.mu.Lock()
+		session.cancelRun = nil
+		session.runDone = nil
+		session.mu.Unlock()
+		session.stopBreakoutCloseTimer()
+		if session.State() != StateEnded {
+			session.setState(onMessageFunction, StateDisconnected)
+		}
+		// Leave only waits for this and not the handlers, otherwise a handler calling Leave would be waiting for itself
+		close(runDone)
+		dispatcher.stop()
+		session.mu.Lock()
+		session.dispatcher = nil
+		session.mu.Unlock()
+	}()
 
-			if err := connection.ReadJSON(&message); err != nil {
-				// log.Print("failed to read:", err)
-				return
+	for {
+		err := session.runConnection(ctx, onMessageFunction)
+		var switching *switchingRoom
+		switch {
+		case session.hasLeft():
+			return nil
+		case errors.As(err, &switching):
+			// JoinBreakoutRoom or ReturnToMainSession has already set the opt for the room we are moving to
+			session.setState(onMessageFunction, StateConnecting)
+			session.clearRoster()
+			err := session.connect(ctx, true)
+			switching.result <- err
+			if err != nil && (ctx.Err() != nil || session.Reconnect == nil) {
+				return err
 			}
-			// log.Printf("Received message (Evt: %s = %d; Seq: %d): %s", MessageNumberToName[message.Evt], message.Evt, message.Seq, string(message.Body))
-
-			switch message.Evt {
-			/*
-				if we receive a WS_CONF_JOIN_RES message (this is sent along with a bunch of other things when the websocket connection is established) will also store some info from the join response that is necessary for sending chats into the session state
-				important that this is done before any other handling functions
-			*/
-			case WS_CONF_JOIN_RES:
-				var body JoinConferenceResponse
-				if err := json.Unmarshal(message.Body, &body); err != nil {
-					// log.Print("Failed to unmarshal json: %+v", err)
-					return
-				}
-				session.JoinInfo = body
-			/* figure out whether we are in the waiting room or not */
-			case WS_CONF_HOLD_CHANGE_INDICATION:
-				var body ConferenceHoldChangeIndication
-				if err := json.Unmarshal(message.Body, &body); err != nil {
-					// log.Print("Failed to unmarshal json: %+v", err)
-					return
+			if err != nil {
+				if err := session.reconnect(ctx, onMessageFunction, err); err != nil {
+					if session.hasLeft() {
+						return nil
+					}
+					return err
 				}
-				if body.BHold == true {
-					wasInWaitingRoom = true
+			}
+		case err == errLeftWaitingRoom:
+			// we were let out of the waiting room, so get new tokens and join the main meeting using the opt we got in the waiting room
+			session.setState(onMessageFunction, StateConnecting)
+			if err := session.connect(ctx, true); err != nil {
+				if ctx.Err() != nil || session.Reconnect == nil {
+					return err
 				}
-			/* get the opt for the waiting room */
-			case WS_CONF_OPTION_INDICATION:
-				if wasInWaitingRoom {
-					var body ConferenceOptionIndication
-					if err := json.Unmarshal(message.Body, &body); err != nil {
-						// log.Print("Failed to unmarshal json: %+v", err)
-						return
+				if err := session.reconnect(ctx, onMessageFunction, err); err != nil {
+					if session.hasLeft() {
+						return nil
 					}
-					session.meetingOpt = body.Opt
+					return err
 				}
 			}
-
-			// dont run the user defined functions in the waiting room
-			if !wasInWaitingRoom {
-				// convert generic json message to go type
-				m, err := GetMessageBody(&message)
-				if err != nil {
-					// log.Printf("Decoding message failed: %+v", err)
-					continue
-				}
-				if err := onMessageFunction(session, m); err != nil {
-					// log.Printf("User defined function failed: %+v", err)
+		case err == nil || err == ErrWaitingRoomTimeout || errors.As(err, new(*HandlerError)) || ctx.Err() != nil || session.Reconnect == nil:
+			return err
+		default:
+			if err := session.reconnect(ctx, onMessageFunction, err); err != nil {
+				if session.hasLeft() {
+					return nil
 				}
+				return err
 			}
 		}
+	}
+}
+
+// Leave sends a leave request so zoom shows us as having left instead of losing connection, waits for the response (or the response timeout), closes the websocket and stops Run.
+// It returns once Run is done with the connection, without waiting for the handlers to finish, so it can be called from a handler (a "leave" chat command for example).  Run returns after the remaining handlers have run.
+func (session *ZoomSession) Leave(ctx context.Context) error {
+	session.mu.Lock()
+	session.left = true
+	cancelRun, runDone := session.cancelRun, session.runDone
+	connection := session.websocketConnection
+	session.mu.Unlock()
+
+	if connection == nil {
+		return ErrNotConnected
+	}
+
+	if cancelRun == nil {
+		// nothing is reading from the connection so there is no point waiting for a response, just close it ourselves
+		session.SendMessage(connection, WS_CONF_LEAVE_REQ, ConferenceLeaveRequest{})
+		connection.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(closeTimeout))
+		return connection.Close()
+	}
+
+	if _, err := session.SendAndWait(ctx, WS_CONF_LEAVE_REQ, ConferenceLeaveRequest{}); err != nil {
+		// we are going to close the connection anyway
+		session.logger.Warn("Leave request failed", "error", err)
+	}
+	cancelRun()
+	select {
+	case <-runDone:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (session *ZoomSession) resetLeft() {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.left = false
+}
+
+func (session *ZoomSession) hasLeft() bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.left
+}
+
+// MakeWebsocketConnection dials websocketUrl and processes messages until the connection fails.
+//
+// Deprecated: use Connect and Run, which can be cancelled and return errors instead of exiting.
+func (session *ZoomSession) MakeWebsocketConnection(websocketUrl string, cookieString string, onMessageFunction onMessage) error {
+	ctx := context.Background()
+	if err := session.ConnectWebsocket(ctx, websocketUrl, cookieString); err != nil {
+		return err
+	}
+	return session.Run(ctx, onMessageFunction)
+}
+
+func (session *ZoomSession) runConnection(ctx context.Context, onMessageFunction onMessage) error {
+	connection := session.currentConnection()
+	defer connection.Close()
+
+	done := make(chan error, 1)
+	enteredWaitingRoom := make(chan struct{}, 1)
+	handlerFailed := make(chan *HandlerError, 1)
+	go func() {
+		done <- session.readMessages(connection, onMessageFunction, enteredWaitingRoom, handlerFailed)
 	}()
 
-	interrupt := make(chan os.Signal, 1)
-	signal.Notify(interrupt, os.Interrupt)
-	// zoom sends pings (aside from regular websocket ones) approximately every minute of the form "{"evt":0,"seq":74}"
-	minutelyJsonPingTicker := time.NewTicker(60 * time.Second)
-	defer minutelyJsonPingTicker.Stop()
+	keepaliveTicker := time.NewTicker(session.keepalive)
+	defer keepaliveTicker.Stop()
+
+	// nil (blocks forever) until we are put in the waiting room
+	var waitingRoomTimeout <-chan time.Time
 
 	for {
-		if wasInWaitingRoom { // get out of the select loop if we are in the waiting room otherwise the function is gonna return and not give us an opportunity to connect to the breakout room
-			break
-		}
 		select {
-		case <-minutelyJsonPingTicker.C:
-			if wasInWaitingRoom { // get out of the select loop if we are in the waiting room otherwise the function is gonna return and not give us an opportunity to connect to the breakout room
-				break
+		case <-enteredWaitingRoom:
+			if session.waitingRoomTimeout > 0 && waitingRoomTimeout == nil {
+				timer := time.NewTimer(session.waitingRoomTimeout)
+				defer timer.Stop()
+				waitingRoomTimeout = timer.C
 			}
-			session.SendMessage(connection, WS_CONN_KEEPALIVE, nil)
-			break
-		case <-done:
-			if wasInWaitingRoom { // get out of the select loop if we are in the waiting room otherwise the function is gonna return and not give us an opportunity to connect to the breakout room
-				break
+		case <-waitingRoomTimeout:
+			if session.State() == StateInWaitingRoom {
+				session.closeConnection(connection, done)
+				return ErrWaitingRoomTimeout
 			}
-			return nil
-		case <-interrupt:
-			// Cleanly close the connection by sending a close message and then
-			// waiting (with timeout) for the server to close the connection.
-			if err := connection.WriteMessage(websocket.CloseMessage, []byte("")); err != nil {
+		case result := <-session.roomSwitches:
+			// we are only leaving this room, not the meeting, so no leave request
+			connection.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(closeTimeout))
+			session.waitForClose(connection, done)
+			return &switchingRoom{result: result}
+		case <-keepaliveTicker.C:
+			if err := session.SendMessage(connection, WS_CONN_KEEPALIVE, nil); err != nil {
 				return err
 			}
-			<-done
-			return nil
+			// the pong extends the read deadline, so this catches connections where zoom is alive but quiet
+			if err := connection.WriteControl(websocket.PingMessage, nil, time.Now().Add(closeTimeout)); err != nil {
+				return err
+			}
+		case err := <-handlerFailed:
+			// WithAbortOnHandlerError is on, the connection is still fine so leave properly
+			session.SendMessage(connection, WS_CONF_LEAVE_REQ, ConferenceLeaveRequest{})
+			connection.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(closeTimeout))
+			session.waitForClose(connection, done)
+			return err
+		case err := <-done:
+			session.pending.fail(ErrConnectionClosed)
+			return err
+		case <-ctx.Done():
+			session.closeConnection(connection, done)
+			return ctx.Err()
 		}
 	}
+}
 
-	if wasInWaitingRoom {
-		meetingInfo, cookieString, err := session.GetMeetingInfoData()
-		if err != nil {
-			log.Fatal(err)
+// closeConnection tells zoom we are leaving (unless Leave already did), sends a close frame and waits for the server to close the connection
+func (session *ZoomSession) closeConnection(connection *websocket.Conn, done <-chan error) {
+	if !session.hasLeft() {
+		leaveCtx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+		session.SendAndWait(leaveCtx, WS_CONF_LEAVE_REQ, ConferenceLeaveRequest{})
+		cancel()
+	}
+
+	connection.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(closeTimeout))
+	session.waitForClose(connection, done)
+}
+
+// waitForClose waits (with timeout) for the server to close the connection after we sent a close frame
+func (session *ZoomSession) waitForClose(connection *websocket.Conn, done <-chan error) {
+	select {
+	case <-done:
+	case <-time.After(closeTimeout):
+		// closing the connection unblocks the reader
+		connection.Close()
+		<-done
+	}
+	session.pending.fail(ErrConnectionClosed)
+}
+
+// reportDecodeError is for the messages the reader needs to understand itself.  the connection is kept since reconnecting won't help if zoom has changed the payload
+func (session *ZoomSession) reportDecodeError(message *GenericZoomMessage, err error) {
+	session.logger.Warn("Decoding message failed", "evt", MessageNumberToName[message.Evt], "seq", message.Seq, "error", err)
+	session.reportError(&DecodeError{Evt: message.Evt, Seq: message.Seq, Raw: message.Body, Err: err})
+}
+
+// extendReadDeadline gives the server another staleTimeout to send us something
+func (session *ZoomSession) extendReadDeadline(connection *websocket.Conn) {
+	if session.staleTimeout > 0 {
+		connection.SetReadDeadline(time.Now().Add(session.staleTimeout))
+	}
+}
+
+// LastKeepalive returns when we last received a keepalive from zoom (the zero time if we haven't yet)
+func (session *ZoomSession) LastKeepalive() time.Time {
+	lastKeepalive, _ := session.lastKeepalive.Load().(time.Time)
+	return lastKeepalive
+}
+
+// enterRoom moves us from Connecting into the meeting or breakout room we joined, once zoom has shown that it isn't holding us in the waiting room
+func (session *ZoomSession) enterRoom(onMessageFunction onMessage) {
+	if session.State() != StateConnecting {
+		return
+	}
+	if session.BreakoutRoom() != "" {
+		session.setState(onMessageFunction, StateInBreakout)
+	} else {
+		session.setState(onMessageFunction, StateInMeeting)
+	}
+}
+
+func (session *ZoomSession) readMessages(connection *websocket.Conn, onMessageFunction onMessage, enteredWaitingRoom chan<- struct{}, handlerFailed chan<- *HandlerError) error {
+	session.extendReadDeadline(connection)
+	connection.SetPongHandler(func(string) error {
+		session.extendReadDeadline(connection)
+		return nil
+	})
+
+	wasInWaitingRoom := false
+	meetingEnded := false
+	joined := false
+	for {
+		var message GenericZoomMessage
+
+		if err := connection.ReadJSON(&message); err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				return ErrConnectionStale
+			}
+			// zoom drops the waiting room connection once it has let us in.  anything else is a normal disconnection and we go back to the waiting room when we reconnect
+			if wasInWaitingRoom && session.State() == StateAdmitted {
+				return errLeftWaitingRoom
+			}
+			// there is nothing to reconnect to once the meeting is over
+			if meetingEnded || websocket.IsCloseError(err, websocket.CloseNormalClosure) {
+				return nil
+			}
+			return err
+		}
+
+		session.extendReadDeadline(connection)
+		session.logMessage("recv", &message)
+		session.pending.resolve(&message)
+
+		switch message.Evt {
+		/*
+			if we receive a WS_CONF_JOIN_RES message (this is sent along with a bunch of other things when the websocket connection is established) will also store some info from the join response that is necessary for sending chats into the session state
+			important that this is done before any other handling functions
+		*/
+		case WS_CONF_JOIN_RES:
+			var body JoinConferenceResponse
+			if err := json.Unmarshal(message.Body, &body); err != nil {
+				session.reportDecodeError(&message, err)
+				continue
+			}
+			session.mu.Lock()
+			session.JoinInfo = body
+			session.mu.Unlock()
+			if session.zak != "" && body.Role != RoleHost {
+				session.logger.Warn("Joined without the host role even though a ZAK was given", "role", body.Role)
+			}
+			// zoom sends this before putting us in the waiting room, so we stay in Connecting until we know we aren't held (see enterRoom)
+			joined = true
+		/* figure out whether we are in the waiting room or not */
+		case WS_CONF_HOLD_CHANGE_INDICATION:
+			var body ConferenceHoldChangeIndication
+			if err := json.Unmarshal(message.Body, &body); err != nil {
+				session.reportDecodeError(&message, err)
+				continue
+			}
+			if body.BHold == true {
+				wasInWaitingRoom = true
+				session.setState(onMessageFunction, StateInWaitingRoom)
+				select {
+				case enteredWaitingRoom <- struct{}{}:
+				default:
+				}
+			} else if session.State() == StateInWaitingRoom {
+				// zoom drops the connection after this and we rejoin the main meeting using the opt
+				session.setState(onMessageFunction, StateAdmitted)
+			} else if joined {
+				session.enterRoom(onMessageFunction)
+			}
+		/* get the opt for the waiting room */
+		case WS_CONF_OPTION_INDICATION:
+			if wasInWaitingRoom {
+				var body ConferenceOptionIndication
+				if err := json.Unmarshal(message.Body, &body); err != nil {
+					session.reportDecodeError(&message, err)
+					continue
+				}
+				session.mu.Lock()
+				session.meetingOpt = body.Opt
+				session.mu.Unlock()
+			}
+		case WS_CONF_END_INDICATION:
+			meetingEnded = true
+			session.setState(onMessageFunction, StateEnded)
+		case WS_CONN_KEEPALIVE:
+			session.lastKeepalive.Store(time.Now())
 		}
-		websocketUrl, err := session.GetWebsocketUrl(meetingInfo, true)
+
+		// user defined functions run in the waiting room too, use session.State() or OnWaitingRoom to tell
+		// convert generic json message to go type
+		m, err := GetMessageBody(&message)
 		if err != nil {
-			log.Fatal(err)
+			session.logger.Debug("Decoding message failed", "evt", MessageNumberToName[message.Evt], "seq", message.Seq, "error", err)
+			session.reportError(err)
+			continue
+		}
+		if message.Evt == WS_CONF_BO_COMMAND_INDICATION || message.Evt == WS_CONF_BO_ATTRIBUTE_INDICATION {
+			session.handleBreakoutMessage(onMessageFunction, m)
+		}
+		if roster, ok := m.(*ConferenceRosterIndication); ok {
+			session.updateRoster(roster)
+			// held participants aren't in the roster
+			userID := session.CurrentJoinInfo().UserID
+			for _, person := range roster.Add {
+				if joined && person.ID == userID && !person.BHold {
+					session.enterRoom(onMessageFunction)
+				}
+			}
+		}
+		// neither are webinar attendees, who get this instead
+		if joined && message.Evt == WS_WEBINAR_VIEW_ONLY_TELEPHONY_INDICATION {
+			session.enterRoom(onMessageFunction)
+		}
+		switch message.Evt {
+		case WS_CONF_ROSTER_INDICATION, WS_WEBINAR_VIEW_ONLY_TELEPHONY_INDICATION, WS_AUDIO_ALLOW_TALK_INDICATION:
+			session.handleWebinarMessage(onMessageFunction, m)
 		}
-		return session.MakeWebsocketConnection(websocketUrl, cookieString, onMessageFunction)
+		evt, seq := message.Evt, message.Seq
+		session.runHandler(func() {
+			if err := session.callHandlers(onMessageFunction, m); err != nil {
+				session.logger.Warn("User defined function failed", "evt", MessageNumberToName[evt], "seq", seq, "error", err)
+				handlerErr := &HandlerError{Evt: evt, Seq: seq, Message: m, Err: err}
+				if session.abortOnHandlerError {
+					// runConnection leaves the meeting
+					select {
+					case handlerFailed <- handlerErr:
+					default:
+					}
+					return
+				}
+				session.reportError(handlerErr)
+			}
+		})
 	}
-
-	return nil
 }
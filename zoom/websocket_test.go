@@ -0,0 +1,249 @@
+package zoom_test
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+// how long tests wait for anything before failing
+const testTimeout = 5 * time.Second
+
+// testOptions makes sessions connect to server, with quick reconnects and no logging
+func testOptions(server *zoomtest.Server) []zoom.Option {
+	return []zoom.Option{
+		zoom.WithCredentials("testKey", "testSecret"),
+		zoom.WithEndpoints(server.Endpoints()),
+		zoom.WithLogger(zoom.NewStdLogger(log.New(ioutil.Discard, "", 0), zoom.LevelError)),
+		zoom.WithReconnectPolicy(&zoom.ReconnectPolicy{
+			InitialDelay: 10 * time.Millisecond,
+			MaxDelay:     50 * time.Millisecond,
+			Multiplier:   2,
+		}),
+	}
+}
+
+// newTestSession makes a session with testOptions followed by options
+func newTestSession(t *testing.T, server *zoomtest.Server, options ...zoom.Option) *zoom.ZoomSession {
+	t.Helper()
+	session, err := zoom.New(zoomtest.MeetingNumber, append(testOptions(server), options...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return session
+}
+
+// startSession connects session and runs it in the background.  the returned channel gets what Run returns
+func startSession(t *testing.T, session *zoom.ZoomSession, onMessageFunction func(session *zoom.ZoomSession, message zoom.Message) error) <-chan error {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if err := session.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- session.Run(context.Background(), onMessageFunction)
+	}()
+	waitForState(t, session, zoom.StateInMeeting)
+	return runErr
+}
+
+// leave leaves the meeting and checks Run stops cleanly
+func leave(t *testing.T, session *zoom.ZoomSession, runErr <-chan error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if err := session.Leave(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitForRun(t, runErr); err != nil {
+		t.Fatalf("Run returned %v after Leave", err)
+	}
+}
+
+func waitForRun(t *testing.T, runErr <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-runErr:
+		return err
+	case <-time.After(testTimeout):
+		t.Fatal("Run did not return")
+		return nil
+	}
+}
+
+func waitForState(t *testing.T, session *zoom.ZoomSession, state zoom.State) {
+	t.Helper()
+	deadline := time.Now().Add(testTimeout)
+	for session.State() != state {
+		if time.Now().After(deadline) {
+			t.Fatalf("State is %s, wanted %s", session.State(), state)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func waitFor(t *testing.T, server *zoomtest.Server, evt int) *zoom.GenericZoomMessage {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	message, err := server.WaitFor(ctx, evt)
+	if err != nil {
+		t.Fatalf("Waiting for %s: %v", zoom.MessageNumberToName[evt], err)
+	}
+	return message
+}
+
+func waitForConnection(t *testing.T, server *zoomtest.Server, n int) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if err := server.WaitForConnection(ctx, n); err != nil {
+		t.Fatalf("Waiting for connection %d: %v", n, err)
+	}
+}
+
+func TestConnectRunLeave(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	runErr := startSession(t, session, nil)
+	if joinInfo := session.CurrentJoinInfo(); joinInfo.ZoomID != server.JoinResponse.ZoomID {
+		t.Errorf("JoinInfo.ZoomID is %q, wanted %q", joinInfo.ZoomID, server.JoinResponse.ZoomID)
+	}
+	if join := server.Joins()[0]; join.Get("zoomid") != "" {
+		t.Errorf("First join sent zoomid %q", join.Get("zoomid"))
+	}
+
+	leave(t, session, runErr)
+	waitFor(t, server, zoom.WS_CONF_LEAVE_REQ)
+	if state := session.State(); state != zoom.StateDisconnected {
+		t.Errorf("State after Leave is %s", state)
+	}
+}
+
+func TestRunStopsWhenContextCancelled(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	if err := session.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	runCtx, stop := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() {
+		runErr <- session.Run(runCtx, nil)
+	}()
+	waitForState(t, session, zoom.StateInMeeting)
+
+	stop()
+	if err := waitForRun(t, runErr); err != context.Canceled {
+		t.Fatalf("Run returned %v, wanted context.Canceled", err)
+	}
+	// we still tell zoom we are going
+	waitFor(t, server, zoom.WS_CONF_LEAVE_REQ)
+}
+
+func TestHandlersReceiveMessages(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	// reply to chats the way a bot would
+	session.OnChat(func(session *zoom.ZoomSession, message *zoom.ConferenceChatIndication) error {
+		return session.SendChatMessage(message.DestNodeID, "echo: "+string(message.Text))
+	})
+	runErr := startSession(t, session, nil)
+
+	if err := server.Send(zoom.WS_CONF_CHAT_INDICATION, zoom.ConferenceChatIndication{
+		DestNodeID: zoom.EVERYONE_CHAT_ID,
+		Text:       []byte("hello"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	message := waitFor(t, server, zoom.WS_CONF_CHAT_REQ)
+	body, err := zoom.GetMessageBody(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text := string(body.(*zoom.ConferenceChatRequest).Text); text != "echo: hello" {
+		t.Errorf("Bot replied %q", text)
+	}
+
+	leave(t, session, runErr)
+}
+
+func TestLeaveFromHandler(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	session := newTestSession(t, server)
+
+	left := make(chan error, 1)
+	session.OnChat(func(session *zoom.ZoomSession, message *zoom.ConferenceChatIndication) error {
+		if string(message.Text) == "++leave" {
+			ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+			defer cancel()
+			left <- session.Leave(ctx)
+		}
+		return nil
+	})
+	runErr := startSession(t, session, nil)
+
+	server.Send(zoom.WS_CONF_CHAT_INDICATION, zoom.ConferenceChatIndication{Text: []byte("++leave")})
+	select {
+	case err := <-left:
+		if err != nil {
+			t.Fatalf("Leave from a handler returned %v", err)
+		}
+	case <-time.After(testTimeout):
+		t.Fatal("Leave from a handler did not return")
+	}
+	if err := waitForRun(t, runErr); err != nil {
+		t.Fatalf("Run returned %v after Leave", err)
+	}
+	waitFor(t, server, zoom.WS_CONF_LEAVE_REQ)
+}
+
+func TestConnectWebsocketCookies(t *testing.T) {
+	sent := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent <- r.Header.Get("Cookie")
+		http.Error(w, "not a websocket", http.StatusBadRequest)
+	}))
+	defer server.Close()
+	session, err := zoom.New(zoomtest.MeetingNumber,
+		zoom.WithCredentials("testKey", "testSecret"),
+		zoom.WithLogger(zoom.NewStdLogger(log.New(ioutil.Discard, "", 0), zoom.LevelError)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverURL, _ := url.Parse(server.URL)
+	session.CookieJar().SetCookies(serverURL, []*http.Cookie{{Name: "_zm_ssid", Value: "fromJar"}})
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	// the handshake fails, we only want the request
+	session.ConnectWebsocket(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/wc/api", "extra=fromString")
+	cookies := strings.Split(<-sent, "; ")
+	sort.Strings(cookies)
+	if strings.Join(cookies, "; ") != "_zm_ssid=fromJar; extra=fromString" {
+		t.Errorf("Sent cookies %q", cookies)
+	}
+}
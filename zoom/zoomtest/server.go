@@ -0,0 +1,468 @@
+// Package zoomtest provides a fake RWG (the zoom websocket server) for testing bots without a real meeting.
+//
+// The server speaks the same JSON protocol as zoom: when a client connects it is sent a WS_CONF_JOIN_RES, a roster indication adding it to the meeting (or what zoom sends instead in waiting rooms and to webinar attendees, see Server) and an attribute indication, followed by keepalives.
+// Tests can then push any other event with Send and check what the bot sent with WaitFor.
+package zoomtest
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/gorilla/websocket"
+)
+
+// returned by Send when there is nobody to send to
+var ErrNoConnections = errors.New("No clients connected")
+
+// MeetingNumber is the meeting number used in the urls returned by Server.URL
+const MeetingNumber = "1234567890"
+
+// MeetingOpt is the opt clients held in the waiting room are given to join the meeting with once they are let in
+const MeetingOpt = "fakeMeetingOpt"
+
+// BreakoutToken is the token the server gives out for the breakout room with the given BID
+func BreakoutToken(bid string) string {
+	return "fakeBotoken-" + bid
+}
+
+// HandlerFunc is called for every message a client sends with the event number it was registered for
+type HandlerFunc func(server *Server, message *zoom.GenericZoomMessage)
+
+type Server struct {
+	// sent to every client when it connects.  UserID and ZoomID are used for the roster indication as well
+	JoinResponse zoom.JoinConferenceResponse
+	// how often the server sends {"evt":0} keepalives, 0 disables them
+	KeepaliveInterval time.Duration
+	// don't answer websocket pings.  together with a KeepaliveInterval of 0 the server never sends anything by itself, like a dead connection
+	IgnorePings bool
+	// report the meeting as a webinar in the meeting info, and treat everyone but the host as an attendee
+	Webinar bool
+	// hold clients that join without an opt in the waiting room.  let them in by sending a WS_CONF_HOLD_CHANGE_INDICATION with bHold false and dropping the connection, like zoom
+	WaitingRoom bool
+
+	server   *httptest.Server
+	upgrader websocket.Upgrader
+
+	mu          sync.Mutex
+	connections []*connection
+	joins       []url.Values
+	received    []zoom.GenericZoomMessage
+	consumed    map[int]int // how many messages of each event number WaitFor has returned
+	changed     chan struct{}
+	handlers    map[int]HandlerFunc
+	sendSeq     uint32
+	infoError   *infoError
+}
+
+type infoError struct {
+	code    int
+	message string
+}
+
+type connection struct {
+	mu sync.Mutex // gorilla/websocket only allows for 1 writer at a time
+	ws *websocket.Conn
+}
+
+func (c *connection) writeJSON(v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ws.WriteJSON(v)
+}
+
+// NewServer starts a fake RWG listening on a random local port.  Call Close when done with it.
+func NewServer() *Server {
+	server := &Server{
+		JoinResponse: zoom.JoinConferenceResponse{
+			ConfID:        "fakeConfID",
+			ConID:         "fakeConID",
+			Mn:            MeetingNumber,
+			ParticipantID: 16778240,
+			UserID:        16778240,
+			UserGUID:      "00000000-0000-0000-0000-000000000000",
+			ZoomID:        "fakeZoomID",
+		},
+		KeepaliveInterval: 60 * time.Second,
+		consumed:          make(map[int]int),
+		changed:           make(chan struct{}),
+		handlers:          make(map[int]HandlerFunc),
+		upgrader: websocket.Upgrader{
+			// clients send the origin of the web sdk page, not ours
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+	// zoom acknowledges leave requests
+	server.Handle(zoom.WS_CONF_LEAVE_REQ, func(server *Server, message *zoom.GenericZoomMessage) {
+		server.Send(zoom.WS_CONF_LEAVE_RES, zoom.ConferenceLeaveResponse{})
+	})
+	// hand out breakout room tokens, clients then reconnect with the token as the opt (see Joins)
+	server.Handle(zoom.WS_CONF_BO_JOIN_REQ, func(server *Server, message *zoom.GenericZoomMessage) {
+		var body zoom.ConferenceBreakoutRoomJoinRequest
+		json.Unmarshal(message.Body, &body)
+		server.Send(zoom.WS_CONF_BO_JOIN_RES, zoom.ConferenceBreakoutRoomJoinResponse{
+			Bid:     body.TargetBID,
+			Botoken: BreakoutToken(body.TargetBID),
+			ConfID:  server.JoinResponse.ConfID,
+		})
+	})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/wc/info", server.serveInfo)
+	mux.HandleFunc("/wc/ping/", server.servePing)
+	mux.HandleFunc("/wc/api/", server.serveWebsocket)
+	server.server = httptest.NewServer(mux)
+	return server
+}
+
+// URL returns the websocket url clients should connect to, see ZoomSession.ConnectWebsocket
+func (server *Server) URL() string {
+	return "ws" + strings.TrimPrefix(server.server.URL, "http") + "/wc/api/" + MeetingNumber
+}
+
+// Endpoints returns endpoints that send the meeting info lookup, RWG ping and websocket of a session to this server, so the whole of ZoomSession.Connect can be exercised
+func (server *Server) Endpoints() zoom.Endpoints {
+	return zoom.Endpoints{
+		Info:            server.server.URL + "/api/v1/wc/info",
+		PingScheme:      "http",
+		WebsocketScheme: "ws",
+	}
+}
+
+// SetInfoError makes meeting info requests fail with the given zoom error code and message (see zoom.MeetingInfoError) until it is called with a code of 0
+func (server *Server) SetInfoError(code int, message string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if code == 0 {
+		server.infoError = nil
+		return
+	}
+	server.infoError = &infoError{code: code, message: message}
+}
+
+// Close disconnects all clients and shuts the server down
+func (server *Server) Close() {
+	server.DropConnections()
+	server.server.Close()
+}
+
+// Handle registers fn to be called whenever a client sends a message with event number evt, replacing any previous handler for it
+func (server *Server) Handle(evt int, fn HandlerFunc) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.handlers[evt] = fn
+}
+
+// Send sends a message to every connected client
+func (server *Server) Send(evt int, body interface{}) error {
+	server.mu.Lock()
+	server.sendSeq++
+	message := zoom.GenericZoomMessage{
+		Evt: evt,
+		Seq: server.sendSeq,
+	}
+	connections := append([]*connection(nil), server.connections...)
+	server.mu.Unlock()
+	if len(connections) == 0 {
+		return ErrNoConnections
+	}
+
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		message.Body = bodyBytes
+	}
+	for _, c := range connections {
+		if err := c.writeJSON(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropConnections closes every client connection without a close frame, like a network failure would
+func (server *Server) DropConnections() {
+	server.mu.Lock()
+	connections := server.connections
+	server.connections = nil
+	server.mu.Unlock()
+
+	for _, c := range connections {
+		c.ws.Close()
+	}
+}
+
+// Joins returns the query parameters of every websocket connection made so far, in order
+func (server *Server) Joins() []url.Values {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return append([]url.Values(nil), server.joins...)
+}
+
+// Received returns every message clients have sent so far, in order
+func (server *Server) Received() []zoom.GenericZoomMessage {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return append([]zoom.GenericZoomMessage(nil), server.received...)
+}
+
+// WaitFor blocks until a client sends a message with event number evt and returns it.  Each call returns the next such message, so calling it twice waits for two messages.
+func (server *Server) WaitFor(ctx context.Context, evt int) (*zoom.GenericZoomMessage, error) {
+	for {
+		server.mu.Lock()
+		seen := 0
+		for i := range server.received {
+			if server.received[i].Evt != evt {
+				continue
+			}
+			if seen == server.consumed[evt] {
+				server.consumed[evt]++
+				message := server.received[i]
+				server.mu.Unlock()
+				return &message, nil
+			}
+			seen++
+		}
+		changed := server.changed
+		server.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// WaitForConnection blocks until at least n websocket connections have been made
+func (server *Server) WaitForConnection(ctx context.Context, n int) error {
+	for {
+		server.mu.Lock()
+		joined := len(server.joins)
+		changed := server.changed
+		server.mu.Unlock()
+		if joined >= n {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// notify wakes up everything blocked in WaitFor and WaitForConnection.  must be called with mu held
+func (server *Server) notify() {
+	close(server.changed)
+	server.changed = make(chan struct{})
+}
+
+// rwgHost is where the meeting info sends clients for the ping and websocket.  like zoom it is a different host from the info endpoint (localhost rather than 127.0.0.1), so cookies set by one aren't sent to the other
+func (server *Server) rwgHost() string {
+	return strings.Replace(strings.TrimPrefix(server.server.URL, "http://"), "127.0.0.1", "localhost", 1)
+}
+
+// serveInfo answers the meeting info request in the same JSONP format as zoom, pointing at this server as the only RWG
+func (server *Server) serveInfo(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	server.mu.Lock()
+	infoErr := server.infoError
+	server.mu.Unlock()
+	if infoErr == nil && query.Get("meetingNumber") != MeetingNumber {
+		infoErr = &infoError{code: 3001, message: "Meeting does not exist: " + query.Get("meetingNumber") + "."}
+	}
+	if infoErr != nil {
+		writeJsonp(w, query.Get("callback"), map[string]interface{}{
+			"status":       false,
+			"errorCode":    infoErr.code,
+			"errorMessage": infoErr.message,
+		})
+		return
+	}
+	rwc, _ := json.Marshal(map[string]string{server.rwgHost(): "fakeRwcAuth"})
+	isWebinar := "0"
+	if server.Webinar {
+		isWebinar = "1"
+	}
+	// zoom sets a session cookie here, clients are expected to send it back with the ping and websocket
+	http.SetCookie(w, &http.Cookie{Name: "_zm_ssid", Value: "fakeSessionID", Path: "/", HttpOnly: true})
+	writeJsonp(w, query.Get("callback"), map[string]interface{}{
+		"status":    true,
+		"errorCode": 0,
+		"result": map[string]interface{}{
+			"meetingNumber": MeetingNumber,
+			"userName":      query.Get("userName"),
+			"passWord":      query.Get("passWord"),
+			"encryptedRWC":  string(rwc),
+			"auth":          "fakeAuth",
+			"sign":          "fakeSign",
+			"mid":           "fakeMid",
+			"tid":           "fakeTid",
+			"ts":            "1600000000000",
+			"isWebinar":     isWebinar,
+		},
+	})
+}
+
+func writeJsonp(w http.ResponseWriter, callback string, body interface{}) {
+	bodyBytes, _ := json.Marshal(body)
+	w.Header().Set("Content-Type", "application/javascript")
+	w.Write([]byte(callback + "(" + string(bodyBytes) + ")"))
+}
+
+func (server *Server) servePing(w http.ResponseWriter, r *http.Request) {
+	// the RWG sets its own cookie for the websocket
+	http.SetCookie(w, &http.Cookie{Name: "_zm_rwg", Value: "fakeRwgSession", Path: "/wc", HttpOnly: true})
+	json.NewEncoder(w).Encode(zoom.RwgInfo{
+		Rwg:     server.rwgHost(),
+		RwcAuth: r.URL.Query().Get("rwcToken"),
+	})
+}
+
+func (server *Server) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	ws, err := server.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &connection{ws: ws}
+	defer ws.Close()
+	if server.IgnorePings {
+		ws.SetPingHandler(func(string) error { return nil })
+	}
+
+	if err := server.sendJoinMessages(c, r.URL.Query()); err != nil {
+		return
+	}
+
+	// only counts as connected once the join messages are out so that anything sent with Send arrives after them
+	server.mu.Lock()
+	server.connections = append(server.connections, c)
+	server.joins = append(server.joins, r.URL.Query())
+	server.notify()
+	server.mu.Unlock()
+
+	done := make(chan struct{})
+	defer close(done)
+	if server.KeepaliveInterval > 0 {
+		go server.keepalive(c, done)
+	}
+
+	for {
+		var message zoom.GenericZoomMessage
+		if err := ws.ReadJSON(&message); err != nil {
+			server.removeConnection(c)
+			return
+		}
+
+		server.mu.Lock()
+		server.received = append(server.received, message)
+		handler := server.handlers[message.Evt]
+		server.notify()
+		server.mu.Unlock()
+
+		if handler != nil {
+			handler(server, &message)
+		}
+	}
+}
+
+func (server *Server) removeConnection(c *connection) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	for i := range server.connections {
+		if server.connections[i] == c {
+			server.connections = append(server.connections[:i], server.connections[i+1:]...)
+			return
+		}
+	}
+}
+
+// sends what zoom sends right after the websocket is opened
+func (server *Server) sendJoinMessages(c *connection, query url.Values) error {
+	server.mu.Lock()
+	joinResponse := server.JoinResponse
+	server.mu.Unlock()
+
+	// if the client is rejoining it keeps its identity
+	if zoomID := query.Get("zoomid"); zoomID != "" {
+		joinResponse.ZoomID = zoomID
+	}
+	// a ZAK makes you the host
+	if query.Get("zak") != "" {
+		joinResponse.Role = zoom.RoleHost
+	}
+	name, _ := base64.StdEncoding.DecodeString(query.Get("dn2"))
+
+	type message struct {
+		evt  int
+		body interface{}
+	}
+	messages := []message{
+		{zoom.WS_CONF_JOIN_RES, joinResponse},
+	}
+	if server.WaitingRoom && query.Get("opt") == "" {
+		// held participants aren't in the roster, they get the opt for the meeting instead
+		messages = append(messages,
+			message{zoom.WS_CONF_HOLD_CHANGE_INDICATION, zoom.ConferenceHoldChangeIndication{BHold: true}},
+			message{zoom.WS_CONF_OPTION_INDICATION, zoom.ConferenceOptionIndication{Opt: MeetingOpt}},
+		)
+	} else if server.Webinar && joinResponse.Role != zoom.RoleHost {
+		// webinar attendees are left out of the roster and are told how to listen by phone instead
+		messages = append(messages, message{zoom.WS_WEBINAR_VIEW_ONLY_TELEPHONY_INDICATION, zoom.WebinarViewOnlyTelephonyIndication{}})
+	} else {
+		messages = append(messages, message{zoom.WS_CONF_ROSTER_INDICATION, zoom.ConferenceRosterIndication{
+			Add: []zoom.RosterAddItem{{
+				ID:     joinResponse.UserID,
+				Dn2:    name,
+				ZoomID: joinResponse.ZoomID,
+			}},
+		}})
+	}
+	messages = append(messages, message{zoom.WS_CONF_ATTRIBUTE_INDICATION, zoom.ConferenceAttributeIndication{
+		"bCanUnmuteVideo": true,
+	}})
+	for _, m := range messages {
+		bodyBytes, err := json.Marshal(m.body)
+		if err != nil {
+			return err
+		}
+		server.mu.Lock()
+		server.sendSeq++
+		message := zoom.GenericZoomMessage{Evt: m.evt, Seq: server.sendSeq, Body: bodyBytes}
+		server.mu.Unlock()
+		if err := c.writeJSON(message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (server *Server) keepalive(c *connection, done chan struct{}) {
+	ticker := time.NewTicker(server.KeepaliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			server.mu.Lock()
+			server.sendSeq++
+			message := zoom.GenericZoomMessage{Evt: zoom.WS_CONN_KEEPALIVE, Seq: server.sendSeq}
+			server.mu.Unlock()
+			if err := c.writeJSON(message); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package zoomtest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/chris124567/zoomer/zoom"
+	"github.com/chris124567/zoomer/zoom/zoomtest"
+)
+
+func TestServer(t *testing.T) {
+	server := zoomtest.NewServer()
+	defer server.Close()
+	if err := server.Send(zoom.WS_CONN_KEEPALIVE, nil); err != zoomtest.ErrNoConnections {
+		t.Errorf("Send without clients returned %v", err)
+	}
+
+	session, err := zoom.New(zoomtest.MeetingNumber, zoom.WithCredentials("testKey", "testSecret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := session.ConnectWebsocket(ctx, server.URL(), ""); err != nil {
+		t.Fatal(err)
+	}
+	go session.Run(ctx, nil)
+
+	if err := server.WaitForConnection(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Send(zoom.WS_CONF_CHAT_INDICATION, zoom.ConferenceChatIndication{Text: []byte("hello")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// each WaitFor gets the next message
+	session.SendChatMessage(zoom.EVERYONE_CHAT_ID, "first")
+	session.SendChatMessage(zoom.EVERYONE_CHAT_ID, "second")
+	for _, want := range []string{"first", "second"} {
+		message, err := server.WaitFor(ctx, zoom.WS_CONF_CHAT_REQ)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := zoom.GetMessageBody(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if text := string(body.(*zoom.ConferenceChatRequest).Text); text != want {
+			t.Errorf("Got chat %q, wanted %q", text, want)
+		}
+	}
+	if received := len(server.Received()); received < 2 {
+		t.Errorf("Received has %d messages", received)
+	}
+
+	if err := session.Leave(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.WaitFor(ctx, zoom.WS_CONF_LEAVE_REQ); err != nil {
+		t.Fatal(err)
+	}
+}